@@ -0,0 +1,164 @@
+package aseprite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mevdschee/raylib-go-mines/sprites"
+)
+
+type rect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type frame struct {
+	Filename string `json:"filename"`
+	Frame    rect   `json:"frame"`
+	Rotated  bool   `json:"rotated"`
+	Duration int    `json:"duration"`
+}
+
+type frameTag struct {
+	Name      string `json:"name"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Direction string `json:"direction"`
+}
+
+type sheet struct {
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		FrameTags []frameTag `json:"frameTags"`
+	} `json:"meta"`
+}
+
+// Read reads a sprite sheet exported by Aseprite in JSON (array or hash
+// variant) as a sprite with explicit frames and animations from the tags
+func Read(name string, data []byte) (*sprites.Sprite, error) {
+	s := sheet{}
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, err
+	}
+	frames, err := readFrames(s.Frames)
+	if err != nil {
+		return nil, err
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("Sprite sheet '%s' has no frames", name)
+	}
+	sprite := sprites.Sprite{
+		Name:       name,
+		Count:      len(frames),
+		Frames:     []sprites.Frame{},
+		Animations: []sprites.Animation{},
+	}
+	for _, f := range frames {
+		if f.Rotated {
+			return nil, fmt.Errorf("Frame '%s' is rotated, export without rotation", f.Filename)
+		}
+		sprite.Frames = append(sprite.Frames, sprites.Frame{
+			X:        f.Frame.X,
+			Y:        f.Frame.Y,
+			Width:    f.Frame.W,
+			Height:   f.Frame.H,
+			Duration: f.Duration,
+		})
+	}
+	sprite.X, sprite.Y = sprite.Frames[0].X, sprite.Frames[0].Y
+	sprite.Width, sprite.Height = sprite.Frames[0].Width, sprite.Frames[0].Height
+	for _, t := range s.Meta.FrameTags {
+		if t.From < 0 || t.To >= len(frames) || t.From > t.To {
+			return nil, fmt.Errorf("Tag '%s' has invalid frame range %d-%d", t.Name, t.From, t.To)
+		}
+		sprite.Animations = append(sprite.Animations, sprites.Animation{
+			Name:      t.Name,
+			From:      t.From,
+			To:        t.To,
+			Direction: direction(t.Direction),
+		})
+	}
+	return &sprite, nil
+}
+
+// ReadSprites reads a sprite sheet exported by Aseprite as a sprite per tag,
+// so that one sheet can hold all sprites of a sprite map. Every sprite has
+// the frames of its tag and the animations of the tags within them (including
+// its own).
+func ReadSprites(data []byte) ([]*sprites.Sprite, error) {
+	sheet, err := Read("sheet", data)
+	if err != nil {
+		return nil, err
+	}
+	result := []*sprites.Sprite{}
+	for _, t := range sheet.Animations {
+		frames := sheet.Frames[t.From : t.To+1]
+		sprite := sprites.Sprite{
+			Name:       t.Name,
+			X:          frames[0].X,
+			Y:          frames[0].Y,
+			Width:      frames[0].Width,
+			Height:     frames[0].Height,
+			Count:      len(frames),
+			Frames:     append([]sprites.Frame{}, frames...),
+			Animations: []sprites.Animation{},
+		}
+		for _, a := range sheet.Animations {
+			if a.From >= t.From && a.To <= t.To {
+				a.From, a.To = a.From-t.From, a.To-t.From
+				sprite.Animations = append(sprite.Animations, a)
+			}
+		}
+		result = append(result, &sprite)
+	}
+	return result, nil
+}
+
+func direction(d string) string {
+	switch d {
+	case "reverse":
+		return sprites.DirectionReverse
+	case "pingpong":
+		return sprites.DirectionPingPong
+	}
+	return sprites.DirectionForward
+}
+
+// readFrames reads the frames in document order from an array or a hash
+func readFrames(data json.RawMessage) ([]frame, error) {
+	frames := []frame{}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return frames, nil
+	}
+	if data[0] == '[' {
+		err := json.Unmarshal(data, &frames)
+		return frames, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token != json.Delim('{') {
+		return nil, fmt.Errorf("Frames must be an array or an object")
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		f := frame{}
+		err = decoder.Decode(&f)
+		if err != nil {
+			return nil, err
+		}
+		f.Filename = token.(string)
+		frames = append(frames, f)
+	}
+	return frames, nil
+}
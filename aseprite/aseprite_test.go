@@ -0,0 +1,119 @@
+package aseprite
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mevdschee/raylib-go-mines/sprites"
+)
+
+func open(t *testing.T, fileName string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", fileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// the frames and tags of the sprite sheets in testdata
+var (
+	sheetFrames = []sprites.Frame{
+		{X: 0, Y: 33, Width: 11, Height: 21, Duration: 100},
+		{X: 12, Y: 33, Width: 11, Height: 21, Duration: 100},
+		{X: 24, Y: 33, Width: 11, Height: 21, Duration: 150},
+		{X: 0, Y: 55, Width: 26, Height: 26, Duration: 200},
+		{X: 27, Y: 55, Width: 26, Height: 26, Duration: 300},
+	}
+	sheetAnimations = []sprites.Animation{
+		{Name: "digits", From: 0, To: 2, Direction: sprites.DirectionForward},
+		{Name: "count", From: 1, To: 2, Direction: sprites.DirectionPingPong},
+		{Name: "buttons", From: 3, To: 4, Direction: sprites.DirectionReverse},
+	}
+)
+
+func TestRead(t *testing.T) {
+	expected := &sprites.Sprite{
+		Name:       "skin",
+		X:          0,
+		Y:          33,
+		Width:      11,
+		Height:     21,
+		Count:      5,
+		Frames:     sheetFrames,
+		Animations: sheetAnimations,
+	}
+	for _, fileName := range []string{"array.json", "hash.json"} {
+		sprite, err := Read("skin", open(t, fileName))
+		if err != nil {
+			t.Fatalf("%s: %v", fileName, err)
+		}
+		if !reflect.DeepEqual(sprite, expected) {
+			t.Errorf("%s: expected %+v, got %+v", fileName, expected, sprite)
+		}
+	}
+}
+
+func TestReadSprites(t *testing.T) {
+	expected := []*sprites.Sprite{
+		{
+			Name: "digits", X: 0, Y: 33, Width: 11, Height: 21, Count: 3,
+			Frames: sheetFrames[0:3],
+			Animations: []sprites.Animation{
+				{Name: "digits", From: 0, To: 2, Direction: sprites.DirectionForward},
+				{Name: "count", From: 1, To: 2, Direction: sprites.DirectionPingPong},
+			},
+		},
+		{
+			Name: "count", X: 12, Y: 33, Width: 11, Height: 21, Count: 2,
+			Frames: sheetFrames[1:3],
+			Animations: []sprites.Animation{
+				{Name: "count", From: 0, To: 1, Direction: sprites.DirectionPingPong},
+			},
+		},
+		{
+			Name: "buttons", X: 0, Y: 55, Width: 26, Height: 26, Count: 2,
+			Frames: sheetFrames[3:5],
+			Animations: []sprites.Animation{
+				{Name: "buttons", From: 0, To: 1, Direction: sprites.DirectionReverse},
+			},
+		},
+	}
+	for _, fileName := range []string{"array.json", "hash.json"} {
+		spriteList, err := ReadSprites(open(t, fileName))
+		if err != nil {
+			t.Fatalf("%s: %v", fileName, err)
+		}
+		if !reflect.DeepEqual(spriteList, expected) {
+			t.Errorf("%s: expected %+v, got %+v", fileName, expected, spriteList)
+		}
+	}
+}
+
+func TestReadRejectsInvalidSheets(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		error string
+	}{
+		{"rotated frame", string(open(t, "rotated.json")), "Frame 'icons 1.aseprite' is rotated"},
+		{"no frames", `{"frames":[],"meta":{}}`, "has no frames"},
+		{"missing frames", `{"meta":{}}`, "has no frames"},
+		{"frames of another type", `{"frames":"icons.png"}`, "must be an array or an object"},
+		{"tag after the last frame", `{"frames":[{"frame":{"w":1,"h":1}}],"meta":{"frameTags":[{"name":"all","from":0,"to":1}]}}`, "invalid frame range 0-1"},
+		{"reversed tag", `{"frames":[{"frame":{"w":1,"h":1}},{"frame":{"w":1,"h":1}}],"meta":{"frameTags":[{"name":"back","from":1,"to":0}]}}`, "invalid frame range 1-0"},
+		{"invalid json", `{"frames":[`, "unexpected end"},
+	}
+	for _, test := range tests {
+		_, err := Read("sheet", []byte(test.data))
+		if err == nil || !strings.Contains(err.Error(), test.error) {
+			t.Errorf("%s: expected error '%s', got %v", test.name, test.error, err)
+		}
+		if _, err := ReadSprites([]byte(test.data)); err == nil {
+			t.Errorf("%s: expected an error reading the sprites", test.name)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Cue names of the game events that have a sound
+const (
+	CueReveal    = "reveal"
+	CueFloodFill = "floodfill"
+	CueFlag      = "flag"
+	CueChord     = "chord"
+	CueExplode   = "explode"
+	CueWin       = "win"
+	CueTick      = "tick"
+)
+
+// Cues are all the cues the game may play
+var Cues = []string{CueReveal, CueFloodFill, CueFlag, CueChord, CueExplode, CueWin, CueTick}
+
+// Backend loads and plays sounds
+type Backend interface {
+	Load(cue, fileName string) error
+	Play(cue string, volume float32)
+	Close()
+}
+
+// Bank maps cue names to WAV or OGG files
+type Bank map[string]string
+
+// BankFromDir creates a bank from the files in a directory named after the cues (e.g. "flag.wav")
+func BankFromDir(dir string) (Bank, error) {
+	bank := Bank{}
+	for _, cue := range Cues {
+		for _, ext := range []string{".wav", ".ogg"} {
+			fileName := filepath.Join(dir, cue+ext)
+			if _, err := os.Stat(fileName); err == nil {
+				bank[cue] = fileName
+				break
+			}
+		}
+	}
+	if len(bank) == 0 {
+		return nil, fmt.Errorf("BankFromDir: no sounds found in '%s'", dir)
+	}
+	return bank, nil
+}
+
+// Player plays cues on a backend taking volume and mute into account
+type Player struct {
+	backend Backend
+	loaded  map[string]bool
+	volume  float32
+	muted   bool
+}
+
+// New creates a new player on a backend
+func New(backend Backend) *Player {
+	return &Player{
+		backend: backend,
+		loaded:  map[string]bool{},
+		volume:  1,
+	}
+}
+
+// Load loads the sounds of a bank into the backend
+func (p *Player) Load(bank Bank) error {
+	for cue, fileName := range bank {
+		err := p.backend.Load(cue, fileName)
+		if err != nil {
+			return fmt.Errorf("Load cue '%s': %v", cue, err)
+		}
+		p.loaded[cue] = true
+	}
+	return nil
+}
+
+// Play plays a cue, cues without a sound are ignored
+func (p *Player) Play(cue string) {
+	if p.muted || p.volume <= 0 || !p.loaded[cue] {
+		return
+	}
+	p.backend.Play(cue, p.volume)
+}
+
+// SetVolume sets the volume between 0 and 1
+func (p *Player) SetVolume(volume float32) {
+	if volume < 0 {
+		volume = 0
+	}
+	if volume > 1 {
+		volume = 1
+	}
+	p.volume = volume
+}
+
+// GetVolume gets the volume
+func (p *Player) GetVolume() float32 {
+	return p.volume
+}
+
+// SetMuted sets whether or not the player is muted
+func (p *Player) SetMuted(muted bool) {
+	p.muted = muted
+}
+
+// IsMuted returns whether or not the player is muted
+func (p *Player) IsMuted() bool {
+	return p.muted
+}
+
+// Close closes the backend
+func (p *Player) Close() {
+	p.backend.Close()
+}
@@ -0,0 +1,48 @@
+package audio
+
+import (
+	"os"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Raylib is a backend that plays sounds on the audio device using raylib
+type Raylib struct {
+	sounds map[string]rl.Sound
+}
+
+// NewRaylib initializes the audio device and creates a new raylib backend
+func NewRaylib() *Raylib {
+	rl.InitAudioDevice()
+	return &Raylib{sounds: map[string]rl.Sound{}}
+}
+
+// Load loads a WAV or OGG file as the sound for a cue
+func (r *Raylib) Load(cue, fileName string) error {
+	if _, err := os.Stat(fileName); err != nil {
+		return err
+	}
+	if sound, ok := r.sounds[cue]; ok {
+		rl.UnloadSound(sound)
+	}
+	r.sounds[cue] = rl.LoadSound(fileName)
+	return nil
+}
+
+// Play plays the sound of a cue
+func (r *Raylib) Play(cue string, volume float32) {
+	sound, ok := r.sounds[cue]
+	if !ok {
+		return
+	}
+	rl.SetSoundVolume(sound, volume)
+	rl.PlaySound(sound)
+}
+
+// Close unloads the sounds and closes the audio device
+func (r *Raylib) Close() {
+	for _, sound := range r.sounds {
+		rl.UnloadSound(sound)
+	}
+	rl.CloseAudioDevice()
+}
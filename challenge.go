@@ -0,0 +1,75 @@
+package main
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/mevdschee/raylib-go-mines/daily"
+)
+
+// setDaily plays the board of the day with the fixed difficulty of the daily
+// mode, like a shared code the game starts with the start cell revealed
+func (c *config) setDaily(date string) error {
+	code, err := daily.Code(date)
+	if err != nil {
+		return err
+	}
+	c.width, c.height, c.bombs = code.Width, code.Height, code.Bombs
+	c.seed, c.code, c.board = code.Seed, &code, nil
+	c.firstClick, c.noGuess, c.practice = code.FirstClick, false, false
+	c.daily = date
+	return nil
+}
+
+// getDailyLabel gets the label of the daily button of the menu
+func getDailyLabel(history *daily.History) string {
+	today := daily.Date(time.Now())
+	label := "Daily " + today
+	if history == nil {
+		return label
+	}
+	if _, ok := history.Get(today); ok {
+		label += " (played)"
+	}
+	if current, _ := history.Streaks(today); current > 0 {
+		label += " streak " + strconv.Itoa(current)
+	}
+	return label
+}
+
+// startDaily records the attempt of the day when the daily game starts, only the first attempt is ranked
+func (g *game) startDaily() {
+	if g.c.daily == "" || g.dailyHistory == nil || g.playback != nil {
+		return
+	}
+	g.dailyRanked = g.dailyHistory.Start(g.c.daily)
+	if !g.dailyRanked {
+		return
+	}
+	if err := g.dailyHistory.Save(); err != nil {
+		log.Println(err)
+	}
+}
+
+// finishDaily records the result of the ranked attempt of the day, a game won with help is not a win
+func (g *game) finishDaily() {
+	if !g.dailyRanked || g.stats == nil {
+		return
+	}
+	won := g.state == stateWon && g.hints == 0 && g.undos == 0 && !g.assisted
+	g.dailyHistory.Finish(g.c.daily, won, g.stats.Milliseconds, g.stats.BBBVPerSecond())
+	if err := g.dailyHistory.Save(); err != nil {
+		log.Println(err)
+	}
+}
+
+// getDailyLine gets the line about the daily game for the end-of-game panel
+func (g *game) getDailyLine() string {
+	line := "Daily " + g.c.daily
+	if !g.dailyRanked {
+		return line + ": already played, not ranked"
+	}
+	current, best := g.dailyHistory.Streaks(daily.Date(time.Now()))
+	return line + ": streak " + strconv.Itoa(current) + " (best " + strconv.Itoa(best) + ")"
+}
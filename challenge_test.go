@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mevdschee/raylib-go-mines/daily"
+)
+
+func TestDailyBoardFollowsFromTheDate(t *testing.T) {
+	c := config{scale: 1}
+	if err := c.setDaily("2026-10-19"); err != nil {
+		t.Fatal(err)
+	}
+	g := newTestGame(t, c)
+	if g.state != statePlaying || !g.tiles[daily.StartY][daily.StartX].open {
+		t.Fatalf("expected the game to start with the start cell revealed")
+	}
+	code, _ := daily.Code("2026-10-19")
+	expected := code.Generate()
+	for y := 0; y < g.c.height; y++ {
+		for x := 0; x < g.c.width; x++ {
+			if g.tiles[y][x].bomb != expected.IsBomb(x, y) {
+				t.Fatalf("expected the board of the day at (%d,%d)", x, y)
+			}
+		}
+	}
+	if g.code == nil || g.code.String() != code.String() {
+		t.Errorf("expected the code of the day")
+	}
+}
@@ -2,8 +2,11 @@ package clips
 
 import (
 	"image"
+	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/mevdschee/raylib-go-mines/clocks"
+	"github.com/mevdschee/raylib-go-mines/particles"
 	"github.com/mevdschee/raylib-go-mines/sprites"
 )
 
@@ -15,6 +18,18 @@ type Clip struct {
 	width, height    float32
 	frame            int
 	frames           []rl.Rectangle
+	durations        []int
+	animations       []sprites.Animation
+	animation        *sprites.Animation
+	elapsed          float64
+	step             int
+	emitter          *particles.Emitter
+	clock            clocks.Clock
+	lastTick         time.Time
+	longPress        time.Duration
+	pressedAt        time.Time
+	holding          bool
+	longPressed      bool
 	onPress          func()
 	onLongPress      func()
 	onRelease        func()
@@ -23,11 +38,20 @@ type Clip struct {
 
 // ClipJSON is a clip in JSON
 type ClipJSON struct {
-	Name          string
-	Sprite        string
-	Repeat        string
-	X, Y          string
-	Width, Height string
+	Name             string            `json:"name,omitempty"`
+	Sprite           string            `json:"sprite" schema:"required"`
+	Repeat           string            `json:"repeat,omitempty" schema:"expression"`
+	X                string            `json:"x" schema:"expression,required"`
+	Y                string            `json:"y" schema:"expression,required"`
+	Width            string            `json:"width,omitempty" schema:"expression"`
+	Height           string            `json:"height,omitempty" schema:"expression"`
+	Frame            string            `json:"frame,omitempty" schema:"expression"`
+	Animation        string            `json:"animation,omitempty"`
+	OnPress          string            `json:"onPress,omitempty" schema:"handler"`
+	OnLongPress      string            `json:"onLongPress,omitempty" schema:"handler"`
+	OnRelease        string            `json:"onRelease,omitempty" schema:"handler"`
+	OnReleaseOutside string            `json:"onReleaseOutside,omitempty" schema:"handler"`
+	Emitter          *particles.Config `json:"emitter,omitempty"`
 }
 
 // GetName gets the name of the clip
@@ -35,9 +59,57 @@ func (c *Clip) GetName() string {
 	return c.name
 }
 
+// GetFrame gets the current frame of the clip
+func (c *Clip) GetFrame() int {
+	return c.frame
+}
+
+// GetBounds gets the position and size of the clip (unscaled)
+func (c *Clip) GetBounds() rl.Rectangle {
+	return rl.NewRectangle(c.x, c.y, c.width, c.height)
+}
+
 // New creates a new sprite based clip
 func New(sprite *sprites.Sprite, name string, x, y int) *Clip {
+	frames, durations := getFrames(sprite)
+	width, height := float32(sprite.Width), float32(sprite.Height)
+	if len(sprite.Frames) > 0 {
+		width, height = frames[0].Width, frames[0].Height
+	}
+	return &Clip{
+		texture:    sprite.Texture,
+		name:       name,
+		x:          float32(x),
+		y:          float32(y),
+		width:      width,
+		height:     height,
+		frame:      0,
+		frames:     frames,
+		durations:  durations,
+		animations: sprite.Animations,
+		clock:      clocks.Real{},
+	}
+}
+
+// NewEmitter creates a new particle emitter clip that uses the sprite frames for its particles
+func NewEmitter(sprite *sprites.Sprite, name string, x, y int, config particles.Config) *Clip {
+	clip := New(sprite, name, x, y)
+	clip.emitter = particles.New(config)
+	return clip
+}
+
+func getFrames(sprite *sprites.Sprite) ([]rl.Rectangle, []int) {
 	frames := []rl.Rectangle{}
+	durations := []int{}
+
+	if len(sprite.Frames) > 0 {
+		for _, f := range sprite.Frames {
+			r := rl.NewRectangle(float32(f.X), float32(f.Y), float32(f.Width), float32(f.Height))
+			frames = append(frames, r)
+			durations = append(durations, f.Duration)
+		}
+		return frames, durations
+	}
 
 	srcWidth, srcHeight := sprite.Width, sprite.Height
 	for i := 0; i < sprite.Count; i++ {
@@ -49,22 +121,28 @@ func New(sprite *sprites.Sprite, name string, x, y int) *Clip {
 		srcY := sprite.Y + (i/grid)*(srcHeight+sprite.Gap)
 		r := rl.NewRectangle(float32(srcX), float32(srcY), float32(srcWidth), float32(srcHeight))
 		frames = append(frames, r)
+		durations = append(durations, 0)
 	}
-
-	return &Clip{
-		texture: sprite.Texture,
-		name:    name,
-		x:       float32(x),
-		y:       float32(y),
-		width:   float32(srcWidth),
-		height:  float32(srcHeight),
-		frame:   0,
-		frames:  frames,
-	}
+	return frames, durations
 }
 
 // NewScaled creates a new 9 slice scaled sprite based clip
 func NewScaled(sprite *sprites.Sprite, name string, x, y, width, height int) *Clip {
+	frames := []rl.Rectangle{rl.NewRectangle(0, 0, float32(width), float32(height))}
+	clip := &Clip{
+		name:   name,
+		x:      float32(x),
+		y:      float32(y),
+		width:  float32(width),
+		height: float32(height),
+		frame:  0,
+		frames: frames,
+		clock:  clocks.Real{},
+	}
+	if sprite.Image == nil {
+		// without an image there is nothing to draw
+		return clip
+	}
 	frame0 := rl.NewImageFromImage(image.NewNRGBA(image.Rect(0, 0, width, height)))
 
 	srcY := sprite.Y
@@ -94,27 +172,28 @@ func NewScaled(sprite *sprites.Sprite, name string, x, y, width, height int) *Cl
 		dstY += dstHeight
 	}
 
-	frames := []rl.Rectangle{rl.NewRectangle(0, 0, float32(width), float32(height))}
-	texture := rl.LoadTextureFromImage(frame0)
+	clip.texture = rl.LoadTextureFromImage(frame0)
 	rl.UnloadImage(frame0)
-
-	return &Clip{
-		texture: texture,
-		name:    name,
-		x:       float32(x),
-		y:       float32(y),
-		width:   float32(width),
-		height:  float32(height),
-		frame:   0,
-		frames:  frames,
-	}
+	return clip
 }
 
 // Draw draws the clip
 func (c *Clip) Draw(scale int) {
 	s := float32(scale)
+	if c.emitter != nil {
+		for _, p := range c.emitter.GetParticles() {
+			if p.Frame < 0 || p.Frame >= len(c.frames) {
+				continue
+			}
+			img := c.frames[p.Frame]
+			x, y := c.x+float32(p.X), c.y+float32(p.Y)
+			color := rl.Fade(rl.White, float32(c.emitter.Alpha(p)))
+			rl.DrawTexturePro(c.texture, img, rl.NewRectangle(x*s, y*s, img.Width*s, img.Height*s), rl.NewVector2(0, 0), 0, color)
+		}
+		return
+	}
 	img := c.frames[c.frame]
-	rl.DrawTexturePro(c.texture, img, rl.NewRectangle(c.x*s, c.y*s, c.width*s, c.height*s), rl.NewVector2(0, 0), 0, rl.White)
+	rl.DrawTexturePro(c.texture, img, rl.NewRectangle(c.x*s, c.y*s, img.Width*s, img.Height*s), rl.NewVector2(0, 0), 0, rl.White)
 }
 
 // GotoFrame goes to a frame of the clip
@@ -124,6 +203,110 @@ func (c *Clip) GotoFrame(frame int) {
 	}
 }
 
+// Play plays a named animation of the sprite, it returns false when it does not exist
+func (c *Clip) Play(animation string) bool {
+	for i := range c.animations {
+		a := c.animations[i]
+		if a.Name != animation || a.From < 0 || a.To >= len(c.frames) || a.From > a.To {
+			continue
+		}
+		c.animation = &a
+		c.elapsed = 0
+		c.step = 1
+		c.frame = a.From
+		if a.Direction == sprites.DirectionReverse {
+			c.step = -1
+			c.frame = a.To
+		}
+		return true
+	}
+	return false
+}
+
+// Stop stops playing the animation and keeps the current frame
+func (c *Clip) Stop() {
+	c.animation = nil
+}
+
+// IsPlaying returns whether or not an animation is playing
+func (c *Clip) IsPlaying() bool {
+	return c.animation != nil
+}
+
+func (c *Clip) animate(dt float64) {
+	a := c.animation
+	if a == nil {
+		return
+	}
+	c.elapsed += dt * 1000
+	for {
+		duration := float64(c.durations[c.frame])
+		if duration <= 0 {
+			duration = 100
+		}
+		if c.elapsed < duration {
+			return
+		}
+		c.elapsed -= duration
+		next := c.frame + c.step
+		if next > a.To || next < a.From {
+			switch a.Direction {
+			case sprites.DirectionPingPong:
+				c.step = -c.step
+				next = c.frame + c.step
+				if next > a.To || next < a.From {
+					next = c.frame
+				}
+			case sprites.DirectionReverse:
+				next = a.To
+			default:
+				next = a.From
+			}
+		}
+		c.frame = next
+	}
+}
+
+// Burst emits n particles at once from a position relative to the clip
+func (c *Clip) Burst(x, y, n int) {
+	if c.emitter != nil {
+		c.emitter.Burst(float64(x), float64(y), n)
+	}
+}
+
+// StartEmitting starts emitting particles from a position relative to the clip
+func (c *Clip) StartEmitting(x, y int) {
+	if c.emitter != nil {
+		c.emitter.Start(float64(x), float64(y))
+	}
+}
+
+// StopEmitting stops emitting new particles
+func (c *Clip) StopEmitting() {
+	if c.emitter != nil {
+		c.emitter.Stop()
+	}
+}
+
+// ResetEmitter removes all particles and restarts the random sequence
+func (c *Clip) ResetEmitter() {
+	if c.emitter != nil {
+		c.emitter.Reset()
+	}
+}
+
+// SetClock sets the clock that drives animations, particles and long presses
+func (c *Clip) SetClock(clock clocks.Clock) {
+	c.clock = clock
+	c.lastTick = clock.Now()
+}
+
+// SetLongPress sets how long the left button must be held down for a long
+// press, zero disables it (the right button always long presses)
+func (c *Clip) SetLongPress(duration time.Duration) {
+	c.longPress = duration
+}
+
 // OnPress sets the click handler function
 func (c *Clip) OnPress(handler func()) {
 	c.onPress = handler
@@ -144,12 +327,26 @@ func (c *Clip) OnReleaseOutside(handler func()) {
 	c.onReleaseOutside = handler
 }
 
-// IsHovered returns whether or not the cursor is hovering the clip
-func (c *Clip) IsHovered(scale int) bool {
-	s := float32(scale)
-	cursor := rl.GetMousePosition()
-	rect := rl.NewRectangle(c.x*s, c.y*s, c.width*s, c.height*s)
-	return rl.CheckCollisionPointRec(cursor, rect)
+// Tick advances the animations and particles to the time of the clock and
+// fires a long press when the left button is held long enough
+func (c *Clip) Tick() {
+	now := c.clock.Now()
+	if c.lastTick.IsZero() {
+		c.lastTick = now
+	}
+	dt := now.Sub(c.lastTick).Seconds()
+	c.lastTick = now
+	c.animate(dt)
+	if c.emitter != nil {
+		c.emitter.Update(dt)
+	}
+	if c.holding && now.Sub(c.pressedAt) >= c.longPress {
+		c.holding = false
+		c.longPressed = true
+		if c.onLongPress != nil {
+			c.onLongPress()
+		}
+	}
 }
 
 // // IsTouched returns whether or not the touch hits the clip
@@ -168,27 +365,43 @@ func (c *Clip) IsHovered(scale int) bool {
 // 	return cursor.In(rect)
 // }
 
-// Update updates the clip
-func (c *Clip) Update(scale int) (err error) {
-	hover := c.IsHovered(scale)
+// Update updates the clip with the pointer
+func (c *Clip) Update(p Pointer) (err error) {
+	hover := c.Contains(p)
 
+	if hover && p.Pressed {
+		c.holding = c.longPress > 0
+		c.longPressed = false
+		c.pressedAt = c.clock.Now()
+	}
+	if !hover {
+		c.holding = false
+	}
 	if c.onPress != nil {
-		if hover && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		if hover && p.Pressed {
 			c.onPress()
 		}
 	}
 	if c.onLongPress != nil {
-		if hover && rl.IsMouseButtonPressed(rl.MouseRightButton) {
+		if hover && p.RightPressed {
 			c.onLongPress()
 		}
 	}
+	if p.Released {
+		c.holding = false
+		if c.longPressed {
+			// the release after a long press cancels the press
+			c.longPressed = false
+			hover = false
+		}
+	}
 	if c.onRelease != nil {
-		if hover && rl.IsMouseButtonReleased(rl.MouseLeftButton) {
+		if hover && p.Released {
 			c.onRelease()
 		}
 	}
 	if c.onReleaseOutside != nil {
-		if !hover && rl.IsMouseButtonReleased(rl.MouseLeftButton) {
+		if !hover && p.Released {
 			c.onReleaseOutside()
 		}
 	}
@@ -0,0 +1,127 @@
+package clips
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mevdschee/raylib-go-mines/clocks"
+	"github.com/mevdschee/raylib-go-mines/sprites"
+)
+
+const tick = time.Second / 30
+
+// events records the handlers that a clip calls
+type events []string
+
+func newTestClip(clock *clocks.Manual, longPress time.Duration, e *events) *Clip {
+	clip := New(&sprites.Sprite{Name: "icons", Width: 16, Height: 16, Count: 1}, "icon", 0, 0)
+	clip.SetClock(clock)
+	clip.SetLongPress(longPress)
+	clip.OnPress(func() { *e = append(*e, "press") })
+	clip.OnLongPress(func() { *e = append(*e, "long") })
+	clip.OnRelease(func() { *e = append(*e, "release") })
+	clip.OnReleaseOutside(func() { *e = append(*e, "outside") })
+	return clip
+}
+
+// hold presses the clip, holds it for a number of ticks and releases it at x
+func hold(clip *Clip, clock *clocks.Manual, ticks int, x float32) {
+	clip.Update(Pointer{X: 8, Y: 8, Pressed: true})
+	for i := 0; i < ticks; i++ {
+		clock.Advance(tick)
+		clip.Tick()
+	}
+	clip.Update(Pointer{X: x, Y: 8, Released: true})
+}
+
+func TestLongPress(t *testing.T) {
+	tests := []struct {
+		name      string
+		longPress time.Duration
+		ticks     int
+		x         float32
+		expected  string
+	}{
+		{"short press", 15 * tick, 14, 8, "press,release"},
+		{"long press at the threshold", 15 * tick, 15, 8, "press,long,outside"},
+		{"long press after the threshold", 15 * tick, 40, 8, "press,long,outside"},
+		{"disabled long press", 0, 40, 8, "press,release"},
+		{"release outside", 15 * tick, 5, 20, "press,outside"},
+	}
+	for _, test := range tests {
+		clock := clocks.NewManual(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+		e := events{}
+		clip := newTestClip(clock, test.longPress, &e)
+		hold(clip, clock, test.ticks, test.x)
+		if got := strings.Join(e, ","); got != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestLongPressFiresOnce(t *testing.T) {
+	clock := clocks.NewManual(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+	e := events{}
+	clip := newTestClip(clock, 15*tick, &e)
+	clip.Update(Pointer{X: 8, Y: 8, Pressed: true})
+	for i := 1; i <= 30; i++ {
+		clock.Advance(tick)
+		clip.Tick()
+		expected := 0
+		if i >= 15 {
+			expected = 1
+		}
+		if got := countEvents(e, "long"); got != expected {
+			t.Fatalf("tick %d: expected %d long presses, got %d", i, expected, got)
+		}
+	}
+}
+
+func TestLeavingCancelsLongPress(t *testing.T) {
+	clock := clocks.NewManual(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+	e := events{}
+	clip := newTestClip(clock, 15*tick, &e)
+	clip.Update(Pointer{X: 8, Y: 8, Pressed: true})
+	clock.Advance(5 * tick)
+	clip.Tick()
+	clip.Update(Pointer{X: 20, Y: 8})
+	clock.Advance(20 * tick)
+	clip.Tick()
+	if got := countEvents(e, "long"); got != 0 {
+		t.Errorf("expected no long press, got %d", got)
+	}
+}
+
+func TestCancelPress(t *testing.T) {
+	clock := clocks.NewManual(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+	e := events{}
+	clip := newTestClip(clock, 15*tick, &e)
+	clip.Update(Pointer{X: 8, Y: 8, Pressed: true})
+	clip.CancelPress()
+	clock.Advance(20 * tick)
+	clip.Tick()
+	if got := countEvents(e, "long"); got != 0 {
+		t.Errorf("expected no long press, got %d", got)
+	}
+}
+
+func TestRightPressIsLongPress(t *testing.T) {
+	clock := clocks.NewManual(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+	e := events{}
+	clip := newTestClip(clock, 0, &e)
+	clip.Update(Pointer{X: 8, Y: 8, RightPressed: true})
+	if got := strings.Join(e, ","); got != "long" {
+		t.Errorf("expected long, got %s", got)
+	}
+}
+
+func countEvents(e events, name string) int {
+	count := 0
+	for _, event := range e {
+		if event == name {
+			count++
+		}
+	}
+	return count
+}
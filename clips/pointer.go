@@ -0,0 +1,43 @@
+package clips
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Pointer is the state of the pointer during a frame, in unscaled coordinates
+type Pointer struct {
+	X            float32
+	Y            float32
+	Pressed      bool
+	Released     bool
+	RightPressed bool
+}
+
+// ReadMouse reads the pointer from the mouse
+func ReadMouse(scale int) Pointer {
+	s := float32(scale)
+	cursor := rl.GetMousePosition()
+	return Pointer{
+		X:            cursor.X / s,
+		Y:            cursor.Y / s,
+		Pressed:      rl.IsMouseButtonPressed(rl.MouseLeftButton),
+		Released:     rl.IsMouseButtonReleased(rl.MouseLeftButton),
+		RightPressed: rl.IsMouseButtonPressed(rl.MouseRightButton),
+	}
+}
+
+// HasButtons returns whether or not a button was pressed or released
+func (p Pointer) HasButtons() bool {
+	return p.Pressed || p.Released || p.RightPressed
+}
+
+// Contains returns whether or not the pointer is on the clip
+func (c *Clip) Contains(p Pointer) bool {
+	return p.X >= c.x && p.X < c.x+c.width && p.Y >= c.y && p.Y < c.y+c.height
+}
+
+// CancelPress forgets a press in progress, so it will not become a long press
+func (c *Clip) CancelPress() {
+	c.holding = false
+	c.longPressed = false
+}
@@ -0,0 +1,69 @@
+package clocks
+
+import (
+	"time"
+)
+
+// Clock tells the time
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is a clock that tells the wall clock time
+type Real struct{}
+
+// Now gets the wall clock time
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Manual is a clock that only moves when it is advanced
+type Manual struct {
+	now time.Time
+}
+
+// NewManual creates a new manual clock that starts at the given time
+func NewManual(start time.Time) *Manual {
+	return &Manual{now: start}
+}
+
+// Now gets the time of the clock
+func (m *Manual) Now() time.Time {
+	return m.now
+}
+
+// Advance moves the clock forward
+func (m *Manual) Advance(d time.Duration) {
+	m.now = m.now.Add(d)
+}
+
+// Stepper advances a manual clock in fixed steps by the elapsed frame time,
+// the time that is left over is kept for the next frame
+type Stepper struct {
+	clock       *Manual
+	step        time.Duration
+	max         time.Duration
+	accumulated time.Duration
+}
+
+// NewStepper creates a new stepper, frame times above max are cut off, so
+// that a slow frame does not cause a burst of steps
+func NewStepper(clock *Manual, step, max time.Duration) *Stepper {
+	return &Stepper{clock: clock, step: step, max: max}
+}
+
+// Advance adds the frame time and advances the clock in steps, it calls the
+// tick function after every step and returns the number of steps
+func (s *Stepper) Advance(frameTime time.Duration, tick func()) int {
+	s.accumulated += frameTime
+	if s.accumulated > s.max {
+		s.accumulated = s.max
+	}
+	steps := 0
+	for ; s.accumulated >= s.step; s.accumulated -= s.step {
+		s.clock.Advance(s.step)
+		tick()
+		steps++
+	}
+	return steps
+}
@@ -0,0 +1,70 @@
+package clocks
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+func TestManual(t *testing.T) {
+	clock := NewManual(start)
+	clock.Advance(time.Second)
+	clock.Advance(time.Second)
+	if got := clock.Now().Sub(start); got != 2*time.Second {
+		t.Errorf("expected 2s, got %v", got)
+	}
+}
+
+func TestStepper(t *testing.T) {
+	ms := time.Millisecond
+	step := 20 * ms
+	tests := []struct {
+		frames []time.Duration
+		steps  []int
+	}{
+		// frames of half a step step every other frame
+		{[]time.Duration{10 * ms, 10 * ms, 10 * ms, 10 * ms}, []int{0, 1, 0, 1}},
+		// frames of a step step every frame
+		{[]time.Duration{step, step, step}, []int{1, 1, 1}},
+		// frames of one and a half steps step three times in two frames
+		{[]time.Duration{30 * ms, 30 * ms}, []int{1, 2}},
+		// a slow frame is cut off at the maximum frame time
+		{[]time.Duration{time.Second, step}, []int{12, 1}},
+		// no frame time, no steps
+		{[]time.Duration{0, 0}, []int{0, 0}},
+	}
+	for i, test := range tests {
+		clock := NewManual(start)
+		stepper := NewStepper(clock, step, 250*time.Millisecond)
+		ticks := 0
+		for j, frameTime := range test.frames {
+			before := ticks
+			steps := stepper.Advance(frameTime, func() {
+				ticks++
+				if got := clock.Now().Sub(start); got != time.Duration(ticks)*step {
+					t.Errorf("test %d: tick %d at %v, expected %v", i, ticks, got, time.Duration(ticks)*step)
+				}
+			})
+			if steps != test.steps[j] || ticks-before != steps {
+				t.Errorf("test %d, frame %d: expected %d steps, got %d (%d ticks)", i, j, test.steps[j], steps, ticks-before)
+			}
+		}
+	}
+}
+
+func TestStepperKeepsRemainder(t *testing.T) {
+	clock := NewManual(start)
+	stepper := NewStepper(clock, 10*time.Millisecond, time.Second)
+	steps := 0
+	for i := 0; i < 100; i++ {
+		steps += stepper.Advance(7*time.Millisecond, func() {})
+	}
+	// 700ms in steps of 10ms, without losing the 7ms that is left over each frame
+	if steps != 70 {
+		t.Errorf("expected 70 steps, got %d", steps)
+	}
+	if got := clock.Now().Sub(start); got != 700*time.Millisecond {
+		t.Errorf("expected 700ms, got %v", got)
+	}
+}
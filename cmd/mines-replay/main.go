@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mevdschee/raylib-go-mines/rawvf"
+	"github.com/mevdschee/raylib-go-mines/replays"
+)
+
+const usage = `Usage: mines-replay <import|export> <file>
+
+Converts between replay files of the game (JSON) and RAW Vienna replays
+(Arbiter, Viennasweeper). "import" reads a RAW Vienna file and prints the
+replay, "export" reads a replay and prints the RAW Vienna file.`
+
+// ticksPerSecond must match the fixed timestep of the game
+const ticksPerSecond = 30
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	data, err := os.ReadFile(os.Args[2])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	var output []byte
+	switch os.Args[1] {
+	case "import":
+		var r *replays.Replay
+		r, err = rawvf.Read(data, ticksPerSecond)
+		if err == nil {
+			output, err = replays.Marshal(r)
+		}
+	case "export":
+		var r *replays.Replay
+		r, err = replays.Unmarshal(data)
+		if err == nil {
+			output, err = rawvf.Write(r)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(output)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mevdschee/raylib-go-mines/movies"
+	"github.com/mevdschee/raylib-go-mines/scenes"
+	"github.com/mevdschee/raylib-go-mines/schemas"
+	"github.com/mevdschee/raylib-go-mines/sprites"
+)
+
+const usage = `Usage: mines-schema <sprites|movie|menu>
+
+Prints the JSON Schema for sprite map, movie scene or menu button documents,
+the schemas are also in the "schemas" directory (e.g. "movie.schema.json").`
+
+// getSchema gets the schema of the documents with the given name
+func getSchema(name string) (schemas.Schema, bool) {
+	switch name {
+	case "sprites":
+		return schemas.New("sprites.schema.json", "Sprite map", sprites.Sprite{}), true
+	case "movie":
+		return schemas.New("movie.schema.json", "Movie scenes", scenes.SceneJSON{}), true
+	case "menu":
+		return schemas.New("menu.schema.json", "Menu buttons", movies.ButtonJSON{}), true
+	}
+	return nil, false
+}
+
+// marshal formats a schema like the files in the "schemas" directory
+func marshal(schema schemas.Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	schema, ok := getSchema(os.Args[1])
+	if !ok {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	data, err := marshal(schema)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(data)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mevdschee/raylib-go-mines/schemas"
+)
+
+// documents are the shipped documents with the name of their schema
+var documents = map[string]string{
+	"../../winxpskin.json": "sprites",
+	"../../movie.json":     "movie",
+	"../../menu.json":      "menu",
+}
+
+func readSchema(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("..", "..", "schemas", name+".schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestSchemasAreUpToDate(t *testing.T) {
+	for _, name := range []string{"sprites", "movie", "menu"} {
+		schema, ok := getSchema(name)
+		if !ok {
+			t.Fatalf("no schema '%s'", name)
+		}
+		data, err := marshal(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, readSchema(t, name)) {
+			t.Errorf("schemas/%s.schema.json is outdated, run: go run ./cmd/mines-schema %s > schemas/%s.schema.json", name, name, name)
+		}
+	}
+}
+
+func TestShippedDocumentsAreValid(t *testing.T) {
+	for fileName, name := range documents {
+		schema := schemas.Schema{}
+		err := json.Unmarshal(readSchema(t, name), &schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := schemas.Validate(schema, data); err != nil {
+			t.Errorf("%s: %v", fileName, err)
+		}
+	}
+}
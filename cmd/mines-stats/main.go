@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mevdschee/raylib-go-mines/engine"
+)
+
+const usage = `Usage: mines-stats <file>...
+
+Prints the 3BV, ZiNi and human ZiNi of boards. A board file is in the text
+board format, or has a line per row with a "*" for every mine (e.g. the
+"Board:" part of a RAW Vienna file).`
+
+// readBoard reads a board in the text format, or the rows of mines of a board file
+func readBoard(data []byte) (*engine.Board, error) {
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "mines ") {
+		board := &engine.Board{}
+		err := board.UnmarshalText(data)
+		return board, err
+	}
+	rows := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			rows = append(rows, line)
+		}
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("readBoard: no rows")
+	}
+	board := engine.NewBoard(len(rows[0]), len(rows))
+	for y, row := range rows {
+		if len(row) != board.Width {
+			return nil, fmt.Errorf("readBoard: expected %d cells in row %d, got %d", board.Width, y+1, len(row))
+		}
+		for x := 0; x < board.Width; x++ {
+			board.SetBomb(x, y, row[x] == '*')
+		}
+	}
+	return board, nil
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, usage)
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	for _, fileName := range flag.Args() {
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		board, err := readBoard(data)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, fileName+":", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s: %dx%d, %d mines, 3BV %d, ZiNi %d, human ZiNi %d\n", fileName, board.Width, board.Height,
+			board.Bombs(), engine.BBBV(board), engine.ZiNi(board), engine.HumanZiNi(board))
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mevdschee/raylib-go-mines/aseprite"
+	"github.com/mevdschee/raylib-go-mines/engine"
+	"github.com/mevdschee/raylib-go-mines/settings"
+	"github.com/mevdschee/raylib-go-mines/sprites"
+	"github.com/mevdschee/raylib-go-mines/xdg"
+)
+
+// skinDir is the directory in the config dir with sprite map images that have
+// the layout of the built-in skin
+const skinDir = "skins"
+
+// newConfig creates the config from the settings
+func newConfig(s settings.Settings) config {
+	c := config{
+		scale:      s.Scale,
+		width:      s.Width,
+		height:     s.Height,
+		bombs:      s.Bombs,
+		holding:    s.Holding,
+		volume:     s.Volume,
+		muted:      s.Muted,
+		noGuess:    s.NoGuess,
+		firstClick: s.FirstClick,
+		questions:  s.Questions,
+		name:       s.Name,
+		skin:       s.Skin,
+		presets:    s.Presets,
+	}
+	if c.name == "" {
+		c.name = getUserName()
+	}
+	return c
+}
+
+// getSettings gets the settings of the config
+func (c config) getSettings() settings.Settings {
+	return settings.Settings{
+		Version:    settings.Version,
+		Width:      c.width,
+		Height:     c.height,
+		Bombs:      c.bombs,
+		Presets:    c.presets,
+		Scale:      c.scale,
+		Skin:       c.skin,
+		FirstClick: c.firstClick,
+		NoGuess:    c.noGuess,
+		Questions:  c.questions,
+		Holding:    c.holding,
+		Volume:     c.volume,
+		Muted:      c.muted,
+		Name:       c.name,
+	}
+}
+
+// setBoard plays a board with an exact layout, with the size and options of the board
+func (c *config) setBoard(board *engine.Board) {
+	c.width, c.height, c.bombs = board.Width, board.Height, board.Bombs()
+	c.questions = board.HasOption("questions")
+	c.practice = board.HasOption("practice")
+	c.seed, c.code, c.board = 0, nil, board
+}
+
+// loadBoardFile reads a board in the text format
+func loadBoardFile(fileName string) (*engine.Board, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	board := &engine.Board{}
+	err = board.UnmarshalText(data)
+	if err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// getPresetName gets the label of a preset, e.g. "30x16x99"
+func getPresetName(p settings.Preset) string {
+	return strconv.Itoa(p.Width) + "x" + strconv.Itoa(p.Height) + "x" + strconv.Itoa(p.Bombs)
+}
+
+// getPreset gets the board size from the arguments of the "preset" action,
+// e.g. call('preset', 16, 16, 40)
+func getPreset(args []interface{}) (settings.Preset, error) {
+	values := []int{}
+	for _, arg := range args {
+		value, ok := arg.(int)
+		if !ok {
+			return settings.Preset{}, fmt.Errorf("getPreset: invalid argument '%v'", arg)
+		}
+		values = append(values, value)
+	}
+	if len(values) != 3 {
+		return settings.Preset{}, fmt.Errorf("getPreset: expected width, height and bombs, got %d arguments", len(values))
+	}
+	p := settings.Preset{Width: values[0], Height: values[1], Bombs: values[2]}
+	if !p.IsValid() {
+		return settings.Preset{}, fmt.Errorf("getPreset: invalid board size %s", getPresetName(p))
+	}
+	return p, nil
+}
+
+// getSkins lists the built-in skin ("") and the PNG files in the skin directory
+func getSkins() []string {
+	skins := []string{""}
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return skins
+	}
+	files, err := filepath.Glob(filepath.Join(dir, skinDir, "*.png"))
+	if err != nil {
+		return skins
+	}
+	for _, file := range files {
+		skins = append(skins, filepath.Base(file))
+	}
+	return skins
+}
+
+// getSkinName gets the label of a skin
+func getSkinName(skin string) string {
+	if skin == "" {
+		return "Windows XP"
+	}
+	return strings.TrimSuffix(skin, filepath.Ext(skin))
+}
+
+// getNextSkin gets the skin after the given one, after the last skin comes the built-in one
+func getNextSkin(skin string) string {
+	skins := getSkins()
+	for i, s := range skins {
+		if s == skin {
+			return skins[(i+1)%len(skins)]
+		}
+	}
+	return skins[0]
+}
+
+// loadSkin reads the sprite map image of a skin
+func loadSkin(skin string) ([]byte, error) {
+	if skin == "" {
+		return spriteMapImage, nil
+	}
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(dir, skinDir, filepath.Base(skin)))
+}
+
+// loadSkinSprites reads the sprites of a skin: those of the built-in sprite
+// map, replaced by the tags of a sprite sheet exported by Aseprite next to the
+// skin image (e.g. "dark.json" for "dark.png"), a skin without a sprite sheet
+// has no sprites of its own and gets nil
+func loadSkinSprites(skin string) ([]*sprites.Sprite, error) {
+	if skin == "" {
+		return nil, nil
+	}
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	skin = filepath.Base(skin)
+	data, err := os.ReadFile(filepath.Join(dir, skinDir, strings.TrimSuffix(skin, filepath.Ext(skin))+".json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sheet, err := aseprite.ReadSprites(data)
+	if err != nil {
+		return nil, fmt.Errorf("loadSkinSprites: %s: %v", skin, err)
+	}
+	spriteList := []*sprites.Sprite{}
+	err = json.Unmarshal([]byte(spriteMapMeta), &spriteList)
+	if err != nil {
+		return nil, err
+	}
+	for _, tagged := range sheet {
+		replaced := false
+		for i, sprite := range spriteList {
+			if sprite.Name == tagged.Name {
+				spriteList[i] = tagged
+				replaced = true
+			}
+		}
+		if !replaced {
+			spriteList = append(spriteList, tagged)
+		}
+	}
+	return spriteList, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mevdschee/raylib-go-mines/xdg"
+)
+
+// writeSkinFile writes a file in the skins directory of the config dir
+func writeSkinFile(t *testing.T, fileName string, data []byte) {
+	t.Helper()
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, skinDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, skinDir, fileName), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSkinSprites(t *testing.T) {
+	g := newTestGame(t, randomConfig(1))
+	if spriteList, err := loadSkinSprites(""); spriteList != nil || err != nil {
+		t.Fatalf("expected no sprites for the built-in skin, got %v", err)
+	}
+	if spriteList, err := loadSkinSprites("plain.png"); spriteList != nil || err != nil {
+		t.Fatalf("expected no sprites for a skin without a sprite sheet, got %v", err)
+	}
+	sheet, err := os.ReadFile("aseprite/testdata/array.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeSkinFile(t, "dark.json", sheet)
+	spriteList, err := loadSkinSprites("dark.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]int{}
+	for _, sprite := range spriteList {
+		byName[sprite.Name] = len(sprite.Frames)
+	}
+	// the tags replace the digits and buttons and add the count
+	expected := map[string]int{"display": 0, "icons": 0, "digits": 3, "buttons": 2, "controls": 0, "field": 0, "count": 2}
+	if len(byName) != len(expected) || len(spriteList) != len(expected) {
+		t.Fatalf("expected sprites %v, got %v", expected, byName)
+	}
+	for name, frames := range expected {
+		if n, ok := byName[name]; !ok || n != frames {
+			t.Errorf("expected sprite %s with %d frames, got %d", name, frames, n)
+		}
+	}
+	// the movie is built on the sprites of the sheet
+	g.setMovie(nil, spriteList)
+	if bounds := g.getClips("time")[0].GetBounds(); bounds.Width != 11 || bounds.Height != 21 {
+		t.Errorf("expected time digits of 11x21, got %v", bounds)
+	}
+	writeSkinFile(t, "broken.json", []byte(`{"frames":[{"frame":{"w":1,"h":1},"rotated":true}]}`))
+	if _, err := loadSkinSprites("broken.png"); err == nil {
+		t.Errorf("expected an error for a rotated frame")
+	}
+}
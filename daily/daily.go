@@ -0,0 +1,223 @@
+package daily
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mevdschee/raylib-go-mines/engine"
+	"github.com/mevdschee/raylib-go-mines/rng"
+	"github.com/mevdschee/raylib-go-mines/xdg"
+)
+
+// the board of the day has a fixed difficulty (intermediate) and starts with
+// the start cell revealed, so that the whole board follows from the date
+const (
+	Width  = 16
+	Height = 16
+	Bombs  = 40
+	StartX = Width / 2
+	StartY = Height / 2
+)
+
+// Version is the version of the daily history file format
+const Version = 1
+
+// dateFormat is the format of the dates of the days
+const dateFormat = "2006-01-02"
+
+// salt makes the daily seeds differ from small seeds that are entered by hand
+const salt = 0x6d696e6573646179
+
+// Date gets the day of a time in UTC, so that everyone has the same board on the same day
+func Date(t time.Time) string {
+	return t.UTC().Format(dateFormat)
+}
+
+// Seed derives the seed of the board of a day from its date, using the number
+// of days since 1970 and the deterministic random number generator
+func Seed(date string) (uint64, error) {
+	t, err := time.Parse(dateFormat, date)
+	if err != nil {
+		return 0, err
+	}
+	days := uint64(t.Unix() / (24 * 60 * 60))
+	return rng.New(days ^ salt).Uint64(), nil
+}
+
+// Code gets the code of the board of a day, the start cell and its neighbours
+// are free, so that revealing it opens up the board
+func Code(date string) (engine.Code, error) {
+	seed, err := Seed(date)
+	if err != nil {
+		return engine.Code{}, err
+	}
+	return engine.Code{
+		Width:      Width,
+		Height:     Height,
+		Bombs:      Bombs,
+		Seed:       seed,
+		X:          StartX,
+		Y:          StartY,
+		FirstClick: engine.FirstClickOpening,
+	}, nil
+}
+
+// Result is the ranked attempt of a day, it is not won until it is finished
+type Result struct {
+	Date          string  `json:"date"`
+	Won           bool    `json:"won"`
+	Finished      bool    `json:"finished"`
+	Milliseconds  int64   `json:"milliseconds"`
+	BBBVPerSecond float64 `json:"3bvPerSecond"`
+}
+
+// History holds the results of the days of a file
+type History struct {
+	fileName string
+	Version  int      `json:"version"`
+	Results  []Result `json:"results"`
+}
+
+// DefaultFileName gets the file of the daily history in the data directory
+func DefaultFileName() (string, error) {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "daily.json"), nil
+}
+
+// New creates an empty history for a file
+func New(fileName string) *History {
+	return &History{fileName: fileName, Version: Version, Results: []Result{}}
+}
+
+// Load reads the history from the file, a missing file is an empty history, a
+// corrupted file is moved aside and returns an empty history with the error
+func Load(fileName string) (*History, error) {
+	h := New(fileName)
+	data, err := os.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	loaded := New(fileName)
+	err = json.Unmarshal(data, loaded)
+	if err == nil && (loaded.Version < 1 || loaded.Version > Version) {
+		err = fmt.Errorf("Load: unsupported daily history version %d", loaded.Version)
+	}
+	if err != nil {
+		os.Rename(fileName, fileName+".corrupt")
+		return h, err
+	}
+	seen := map[string]bool{}
+	for _, r := range loaded.Results {
+		if _, err := time.Parse(dateFormat, r.Date); err == nil && !seen[r.Date] {
+			seen[r.Date] = true
+			h.Results = append(h.Results, r)
+		}
+	}
+	sort.Slice(h.Results, func(i, j int) bool {
+		return h.Results[i].Date < h.Results[j].Date
+	})
+	return h, nil
+}
+
+// Get gets the result of a day
+func (h *History) Get(date string) (Result, bool) {
+	for _, r := range h.Results {
+		if r.Date == date {
+			return r, true
+		}
+	}
+	return Result{}, false
+}
+
+// Start records the attempt of a day, it returns false when the day was
+// already attempted and the game is not ranked
+func (h *History) Start(date string) bool {
+	if _, ok := h.Get(date); ok {
+		return false
+	}
+	h.Results = append(h.Results, Result{Date: date})
+	sort.Slice(h.Results, func(i, j int) bool {
+		return h.Results[i].Date < h.Results[j].Date
+	})
+	return true
+}
+
+// Finish records the outcome of the ranked attempt of a day
+func (h *History) Finish(date string, won bool, milliseconds int64, bbbvPerSecond float64) {
+	for i := range h.Results {
+		if h.Results[i].Date == date && !h.Results[i].Finished {
+			h.Results[i] = Result{
+				Date:          date,
+				Won:           won,
+				Finished:      true,
+				Milliseconds:  milliseconds,
+				BBBVPerSecond: bbbvPerSecond,
+			}
+		}
+	}
+}
+
+// Streaks counts the days in a row that were won up to today (or yesterday
+// when today is not played yet) and the most days in a row that were won
+func (h *History) Streaks(today string) (current, best int) {
+	run := 0
+	previous := time.Time{}
+	for _, r := range h.Results {
+		day, err := time.Parse(dateFormat, r.Date)
+		if err != nil {
+			continue
+		}
+		switch {
+		case !r.Won:
+			run = 0
+		case !previous.IsZero() && day.Sub(previous) == 24*time.Hour && run > 0:
+			run++
+		default:
+			run = 1
+		}
+		previous = day
+		if run > best {
+			best = run
+		}
+	}
+	if len(h.Results) == 0 {
+		return 0, 0
+	}
+	now, err := time.Parse(dateFormat, today)
+	if err != nil {
+		return 0, best
+	}
+	last := h.Results[len(h.Results)-1]
+	lastDay, _ := time.Parse(dateFormat, last.Date)
+	switch {
+	case last.Date == today && !last.Finished:
+		// today is in progress, the streak up to yesterday still counts
+		current = 0
+		if len(h.Results) > 1 {
+			earlier := h.Results[:len(h.Results)-1]
+			current, _ = (&History{Results: earlier}).Streaks(Date(now.Add(-24 * time.Hour)))
+		}
+	case now.Sub(lastDay) <= 24*time.Hour:
+		current = run
+	}
+	return current, best
+}
+
+// Save writes the history to its file atomically
+func (h *History) Save() error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return xdg.WriteFile(h.fileName, data)
+}
@@ -0,0 +1,59 @@
+package daily
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCodePinsTheBoard(t *testing.T) {
+	code, err := Code("2026-10-19")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.String() != "AhAQKAgIo7HB4YT9t-huAiw" {
+		t.Errorf("expected code AhAQKAgIo7HB4YT9t-huAiw, got %s", code)
+	}
+	expected := strings.Join([]string{
+		"mines 16x16 40",
+		"......**..***...",
+		".*......*.*....*",
+		"*..*.........*..",
+		"*..*......*.....",
+		"....**....*....*",
+		".....*.....*....",
+		"*...............",
+		"....*..........*",
+		".............**.",
+		"..*.*...........",
+		"...........*.*..",
+		".....*....*..*..",
+		".......*.....*..",
+		".............**.",
+		"....***.........",
+		"................",
+	}, "\n") + "\n"
+	text, err := code.Generate().MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(text) != expected {
+		t.Errorf("expected board\n%s\ngot\n%s", expected, text)
+	}
+}
+
+func TestCodeOfEveryDayOpensUp(t *testing.T) {
+	for day := 1; day <= 28; day++ {
+		date := "2026-02-" + string(rune('0'+day/10)) + string(rune('0'+day%10))
+		code, err := Code(date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		board := code.Generate()
+		if board.Bombs() != Bombs || board.IsBomb(StartX, StartY) || board.Number(StartX, StartY) != 0 {
+			t.Fatalf("%s: expected %d mines and an opening at the start cell", date, Bombs)
+		}
+	}
+	if _, err := Code("2026-13-01"); err == nil {
+		t.Errorf("expected an error for an invalid date")
+	}
+}
@@ -0,0 +1,115 @@
+package engine
+
+// Board is the layout of the bombs on a minesweeper board, it may also have
+// cells that are open, flagged or question marked, to start a game from that state
+type Board struct {
+	Width    int
+	Height   int
+	Options  []string
+	bombs    []bool
+	open     []bool
+	flagged  []bool
+	question []bool
+}
+
+// NewBoard creates a new board without bombs with all cells covered
+func NewBoard(width, height int) *Board {
+	return &Board{
+		Width:    width,
+		Height:   height,
+		Options:  []string{},
+		bombs:    make([]bool, width*height),
+		open:     make([]bool, width*height),
+		flagged:  make([]bool, width*height),
+		question: make([]bool, width*height),
+	}
+}
+
+// IsBomb returns whether or not there is a bomb on the cell
+func (b *Board) IsBomb(x, y int) bool {
+	return b.bombs[y*b.Width+x]
+}
+
+// SetBomb places or removes a bomb on the cell
+func (b *Board) SetBomb(x, y int, bomb bool) {
+	b.bombs[y*b.Width+x] = bomb
+}
+
+// IsOpen returns whether or not the cell starts open
+func (b *Board) IsOpen(x, y int) bool {
+	return b.open[y*b.Width+x]
+}
+
+// SetOpen sets whether or not the cell starts open
+func (b *Board) SetOpen(x, y int, open bool) {
+	b.open[y*b.Width+x] = open
+}
+
+// IsFlagged returns whether or not the cell starts flagged
+func (b *Board) IsFlagged(x, y int) bool {
+	return b.flagged[y*b.Width+x]
+}
+
+// SetFlagged sets whether or not the cell starts flagged
+func (b *Board) SetFlagged(x, y int, flagged bool) {
+	b.flagged[y*b.Width+x] = flagged
+}
+
+// IsQuestion returns whether or not the cell starts with a question mark
+func (b *Board) IsQuestion(x, y int) bool {
+	return b.question[y*b.Width+x]
+}
+
+// SetQuestion sets whether or not the cell starts with a question mark
+func (b *Board) SetQuestion(x, y int, question bool) {
+	b.question[y*b.Width+x] = question
+}
+
+// HasOption returns whether or not the board has the option (e.g. "questions")
+func (b *Board) HasOption(option string) bool {
+	for _, o := range b.Options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// Bombs counts the bombs on the board
+func (b *Board) Bombs() int {
+	n := 0
+	for _, bomb := range b.bombs {
+		if bomb {
+			n++
+		}
+	}
+	return n
+}
+
+// Number counts the bombs around the cell
+func (b *Board) Number(x, y int) int {
+	n := 0
+	b.ForEachNeighbour(x, y, func(x, y int) {
+		if b.IsBomb(x, y) {
+			n++
+		}
+	})
+	return n
+}
+
+// ForEachNeighbour calls a function for each cell around the cell
+func (b *Board) ForEachNeighbour(x, y int, do func(x, y int)) {
+	for i := 0; i < 9; i++ {
+		dy, dx := i/3-1, i%3-1
+		if dy == 0 && dx == 0 {
+			continue
+		}
+		if y+dy < 0 || x+dx < 0 {
+			continue
+		}
+		if y+dy >= b.Height || x+dx >= b.Width {
+			continue
+		}
+		do(x+dx, y+dy)
+	}
+}
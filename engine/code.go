@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+// version 1 codes have no options
+const codeVersion = 2
+
+const (
+	optionNoGuess    = 1
+	optionFirstClick = 1 << 1 // two bits
+)
+
+// NoGuessAttempts is the number of layouts and repairs that the generation of
+// a no-guess board may try, expert boards need less than a thousand
+const NoGuessAttempts = 2000
+
+// the board sizes that a code can describe
+const (
+	minWidth  = 9
+	maxWidth  = 100
+	minHeight = 9
+	maxHeight = 50
+)
+
+// Code is everything that is needed to reproduce a board
+type Code struct {
+	Width      int
+	Height     int
+	Bombs      int
+	Seed       uint64
+	X, Y       int
+	NoGuess    bool
+	FirstClick FirstClick
+}
+
+// Generate generates the board of the code
+func (c Code) Generate() *Board {
+	if c.NoGuess {
+		board, _ := GenerateNoGuess(c.Width, c.Height, c.Bombs, c.Seed, c.X, c.Y, NoGuessAttempts)
+		return board
+	}
+	return Generate(c.Width, c.Height, c.Bombs, c.Seed, c.X, c.Y, c.FirstClick)
+}
+
+func (c Code) options() uint64 {
+	options := uint64(0)
+	if c.NoGuess {
+		options |= optionNoGuess
+	}
+	options |= uint64(c.FirstClick) * optionFirstClick
+	return options
+}
+
+// String encodes the code as a short URL safe string
+func (c Code) String() string {
+	data := []byte{codeVersion}
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, v := range []uint64{uint64(c.Width), uint64(c.Height), uint64(c.Bombs), uint64(c.X), uint64(c.Y), c.Seed, c.options()} {
+		n := binary.PutUvarint(buf, v)
+		data = append(data, buf[:n]...)
+	}
+	data = append(data, byte(crc32.ChecksumIEEE(data)))
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseCode decodes a code from a string
+func ParseCode(s string) (Code, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(data) < 2 {
+		return Code{}, fmt.Errorf("ParseCode: invalid code '%s'", s)
+	}
+	checksum := data[len(data)-1]
+	data = data[:len(data)-1]
+	if byte(crc32.ChecksumIEEE(data)) != checksum {
+		return Code{}, fmt.Errorf("ParseCode: invalid checksum in code '%s'", s)
+	}
+	version := data[0]
+	if version < 1 || version > codeVersion {
+		return Code{}, fmt.Errorf("ParseCode: unsupported version %d", version)
+	}
+	data = data[1:]
+	values := []uint64{}
+	for len(data) > 0 {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return Code{}, fmt.Errorf("ParseCode: invalid code '%s'", s)
+		}
+		values = append(values, v)
+		data = data[n:]
+	}
+	if version == 1 {
+		values = append(values, 0)
+	}
+	if len(values) != 7 {
+		return Code{}, fmt.Errorf("ParseCode: invalid code '%s'", s)
+	}
+	width, height, bombs, x, y := values[0], values[1], values[2], values[3], values[4]
+	// check before converting, so large values can't wrap around to negative ints
+	if width < minWidth || width > maxWidth || height < minHeight || height > maxHeight ||
+		x >= width || y >= height || bombs < 1 || bombs >= width*height {
+		return Code{}, fmt.Errorf("ParseCode: invalid board in code '%s'", s)
+	}
+	c := Code{
+		Width:      int(width),
+		Height:     int(height),
+		Bombs:      int(bombs),
+		X:          int(x),
+		Y:          int(y),
+		Seed:       values[5],
+		NoGuess:    values[6]&optionNoGuess != 0,
+		FirstClick: FirstClick(values[6] / optionFirstClick % 4),
+	}
+	return c, nil
+}
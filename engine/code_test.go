@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+	"testing"
+)
+
+// encode encodes raw values like Code.String does, without any checks
+func encode(values ...uint64) string {
+	data := []byte{codeVersion}
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, v := range values {
+		n := binary.PutUvarint(buf, v)
+		data = append(data, buf[:n]...)
+	}
+	data = append(data, byte(crc32.ChecksumIEEE(data)))
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func TestParseCode(t *testing.T) {
+	c := Code{Width: 30, Height: 16, Bombs: 99, Seed: 1234, X: 29, Y: 15, NoGuess: true, FirstClick: FirstClickCorner}
+	parsed, err := ParseCode(c.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != c {
+		t.Errorf("expected %+v, got %+v", c, parsed)
+	}
+	for _, c := range []Code{
+		{Width: 9, Height: 9, Bombs: 1},
+		{Width: 100, Height: 50, Bombs: 4999, X: 99, Y: 49},
+	} {
+		if _, err := ParseCode(c.String()); err != nil {
+			t.Errorf("%+v: %v", c, err)
+		}
+	}
+}
+
+func TestParseCodeRejectsInvalidBoards(t *testing.T) {
+	huge := uint64(math.MaxUint64)
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"garbage", "not a code!"},
+		{"bad checksum", "AgkJCgQEKgAO"},
+		{"too few values", encode(9, 9, 10, 4, 4, 42)},
+		{"too many values", encode(9, 9, 10, 4, 4, 42, 0, 0)},
+		{"no bombs", encode(9, 9, 0, 4, 4, 42, 0)},
+		{"board full of bombs", encode(9, 9, 81, 4, 4, 42, 0)},
+		{"too narrow", encode(8, 9, 10, 4, 4, 42, 0)},
+		{"too low", encode(9, 8, 10, 4, 4, 42, 0)},
+		{"too wide", encode(101, 9, 10, 4, 4, 42, 0)},
+		{"too high", encode(9, 51, 10, 4, 4, 42, 0)},
+		{"x outside", encode(9, 9, 10, 9, 4, 42, 0)},
+		{"y outside", encode(9, 9, 10, 4, 9, 42, 0)},
+		{"negative x", encode(9, 9, 10, huge, 4, 42, 0)},
+		{"negative y", encode(9, 9, 10, 4, huge, 42, 0)},
+		{"negative bombs", encode(9, 9, huge, 4, 4, 42, 0)},
+		{"overflowing size", encode(1<<32, 1<<32, 10, 4, 4, 42, 0)},
+		{"huge size", encode(1<<20, 1<<20, 10, 4, 4, 42, 0)},
+	}
+	for _, test := range tests {
+		if c, err := ParseCode(test.code); err == nil {
+			t.Errorf("%s: expected an error, got %+v", test.name, c)
+		}
+	}
+}
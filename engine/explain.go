@@ -0,0 +1,43 @@
+package engine
+
+import (
+	"fmt"
+)
+
+func cellName(v *View, cell int) string {
+	return fmt.Sprintf("row %d, column %d", cell/v.Width+1, cell%v.Width+1)
+}
+
+// Explain describes in a short sentence why the deduction holds
+func Explain(v *View, d Deduction) string {
+	target := cellName(v, d.Y*v.Width+d.X)
+	conclusion := target + " is safe"
+	if d.Bomb {
+		conclusion = target + " is a bomb"
+	}
+	switch d.Rule {
+	case RuleSingle:
+		source := d.Sources[0]
+		number := v.cells[source]
+		if d.Bomb {
+			return fmt.Sprintf("The %d at %s has as many covered neighbours as missing bombs, so %s.", number, cellName(v, source), conclusion)
+		}
+		return fmt.Sprintf("The %d at %s already has all its bombs, so %s.", number, cellName(v, source), conclusion)
+	case RuleSubset:
+		a, b := d.Sources[0], d.Sources[1]
+		na, nb := v.cells[a], v.cells[b]
+		switch {
+		case a/v.Width == b/v.Width:
+			return fmt.Sprintf("By the %d-%d pattern on row %d, %s.", na, nb, a/v.Width+1, conclusion)
+		case a%v.Width == b%v.Width:
+			return fmt.Sprintf("By the %d-%d pattern on column %d, %s.", na, nb, a%v.Width+1, conclusion)
+		}
+		return fmt.Sprintf("The %d at %s and the %d at %s share covered cells, so %s.", na, cellName(v, a), nb, cellName(v, b), conclusion)
+	case RuleGlobal:
+		if d.Bomb {
+			return fmt.Sprintf("Every covered cell left must be a bomb, so %s.", conclusion)
+		}
+		return fmt.Sprintf("All bombs are accounted for, so %s.", conclusion)
+	}
+	return conclusion + "."
+}
@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name      string
+		board     string
+		deduction Deduction
+		expected  string
+	}{
+		{"single bomb", `
+			mines 3x3 1
+			11.
+			1*.
+			...`,
+			Deduction{X: 1, Y: 1, Bomb: true, Rule: RuleSingle, Sources: []int{0}},
+			"The 1 at row 1, column 1 has as many covered neighbours as missing bombs, so row 2, column 2 is a bomb.",
+		},
+		{"single safe", `
+			mines 3x3 1
+			1F.
+			...
+			...`,
+			Deduction{X: 2, Y: 1, Rule: RuleSingle, Sources: []int{0}},
+			"The 1 at row 1, column 1 already has all its bombs, so row 2, column 3 is safe.",
+		},
+		{"subset on a row", `
+			mines 3x3 2
+			...
+			*..
+			12*`,
+			Deduction{X: 2, Y: 1, Rule: RuleSubset, Sources: []int{6, 7}},
+			"By the 1-2 pattern on row 3, row 2, column 3 is safe.",
+		},
+		{"subset on a column", `
+			mines 3x3 2
+			1*.
+			2..
+			*..`,
+			Deduction{X: 1, Y: 2, Rule: RuleSubset, Sources: []int{0, 3}},
+			"By the 1-2 pattern on column 1, row 3, column 2 is safe.",
+		},
+		{"subset", `
+			mines 3x3 1
+			1*.
+			.1.
+			...`,
+			Deduction{X: 2, Y: 0, Rule: RuleSubset, Sources: []int{0, 4}},
+			"The 1 at row 1, column 1 and the 1 at row 2, column 2 share covered cells, so row 1, column 3 is safe.",
+		},
+		{"global bomb", `
+			mines 3x3 1
+			111
+			1*1
+			111`,
+			Deduction{X: 1, Y: 1, Bomb: true, Rule: RuleGlobal},
+			"Every covered cell left must be a bomb, so row 2, column 2 is a bomb.",
+		},
+		{"global safe", `
+			mines 3x3 1
+			F..
+			...
+			...`,
+			Deduction{X: 2, Y: 2, Rule: RuleGlobal},
+			"All bombs are accounted for, so row 3, column 3 is safe.",
+		},
+	}
+	for _, test := range tests {
+		v := getView(parseBoard(t, test.board))
+		if got := Explain(v, test.deduction); got != test.expected {
+			t.Errorf("%s: expected '%s', got '%s'", test.name, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"github.com/mevdschee/raylib-go-mines/rng"
+)
+
+// FirstClick is a policy for what the first click may hit
+type FirstClick int
+
+// First click policies
+const (
+	// FirstClickSafe keeps the cell of the first click free
+	FirstClickSafe FirstClick = iota
+	// FirstClickOpening keeps the first click and its neighbours free, so it opens up
+	FirstClickOpening
+	// FirstClickClassic places the bombs regardless of the first click
+	FirstClickClassic
+	// FirstClickCorner moves a bomb under the first click to the top left corner (or the
+	// first free cell to the right of it) like Windows XP does
+	FirstClickCorner
+)
+
+// FirstClicks are the names of the first click policies
+var FirstClicks = []string{"Safe", "Opening", "Classic", "XP corner"}
+
+func (f FirstClick) String() string {
+	if f < 0 || int(f) >= len(FirstClicks) {
+		return "Unknown"
+	}
+	return FirstClicks[f]
+}
+
+// Generate places the bombs randomly using the seed, the cell of the first
+// click (x,y) is handled according to the policy, the same arguments always
+// give the same board
+func Generate(width, height, bombs int, seed uint64, x, y int, policy FirstClick) *Board {
+	r := rng.New(seed)
+	switch policy {
+	case FirstClickOpening:
+		if bombs <= width*height-9 {
+			return place(width, height, bombs, r, func(cx, cy int) bool {
+				return cx >= x-1 && cx <= x+1 && cy >= y-1 && cy <= y+1
+			})
+		}
+	case FirstClickClassic:
+		return place(width, height, bombs, r, func(cx, cy int) bool {
+			return false
+		})
+	case FirstClickCorner:
+		board := place(width, height, bombs, r, func(cx, cy int) bool {
+			return false
+		})
+		if board.IsBomb(x, y) {
+			for i := range board.bombs {
+				if !board.bombs[i] {
+					board.bombs[i] = true
+					board.SetBomb(x, y, false)
+					break
+				}
+			}
+		}
+		return board
+	}
+	return place(width, height, bombs, r, func(cx, cy int) bool {
+		return cx == x && cy == y
+	})
+}
+
+// place places the bombs randomly on the cells that are not excluded
+func place(width, height, bombs int, r *rng.Source, excluded func(x, y int) bool) *Board {
+	board := NewBoard(width, height)
+	cells := []int{}
+	for i := 0; i < width*height; i++ {
+		if !excluded(i%width, i/width) {
+			cells = append(cells, i)
+		}
+	}
+	if bombs > len(cells) {
+		bombs = len(cells)
+	}
+	for i := 0; i < bombs; i++ {
+		j := i + r.Intn(len(cells)-i)
+		cells[i], cells[j] = cells[j], cells[i]
+		board.bombs[cells[i]] = true
+	}
+	return board
+}
+
+// maxRepairs is the number of bombs that may be moved before a new layout is tried
+const maxRepairs = 50
+
+// GenerateNoGuess generates a board that can be solved from the first click
+// (x,y) by deduction only. It tries layouts and repairs them by moving bombs
+// away from where the solver gets stuck, every layout and every repair is an
+// attempt. When the attempts run out it returns a board that may need
+// guessing and false. The same arguments always give the same board.
+func GenerateNoGuess(width, height, bombs int, seed uint64, x, y int, attempts int) (*Board, bool) {
+	r := rng.New(seed)
+	// keep the first click and its neighbours free when possible, so it opens up
+	excluded := func(cx, cy int) bool {
+		return cx >= x-1 && cx <= x+1 && cy >= y-1 && cy <= y+1
+	}
+	if bombs > width*height-9 {
+		excluded = func(cx, cy int) bool {
+			return cx == x && cy == y
+		}
+	}
+	for {
+		board := place(width, height, bombs, r, excluded)
+		for repairs := 0; repairs < maxRepairs; repairs++ {
+			attempts--
+			view, solved := simulate(board, x, y)
+			if solved {
+				return board, true
+			}
+			if attempts <= 0 {
+				return board, false
+			}
+			if !repair(board, view, r, excluded) {
+				break
+			}
+		}
+	}
+}
+
+// simulate plays the board from the first click using only deductions
+func simulate(board *Board, x, y int) (*View, bool) {
+	view := NewView(board.Width, board.Height, board.Bombs())
+	view.Reveal(board, x, y)
+	for view.Covered() > view.Bombs {
+		progress := false
+		for _, d := range Solve(view) {
+			if !d.Bomb && !view.IsOpen(d.X, d.Y) {
+				view.Reveal(board, d.X, d.Y)
+				progress = true
+			}
+		}
+		if !progress {
+			return view, false
+		}
+	}
+	return view, true
+}
+
+// repair moves a bomb next to the open area to a covered cell away from it
+func repair(board *Board, view *View, r *rng.Source, excluded func(x, y int) bool) bool {
+	frontier, interior := []int{}, []int{}
+	for i, c := range view.cells {
+		if c >= 0 {
+			continue
+		}
+		x, y := i%view.Width, i/view.Width
+		next := false
+		view.forEachNeighbour(i, func(j int) {
+			if view.cells[j] >= 0 {
+				next = true
+			}
+		})
+		if next && board.IsBomb(x, y) {
+			frontier = append(frontier, i)
+		}
+		if !next && !board.IsBomb(x, y) && !excluded(x, y) {
+			interior = append(interior, i)
+		}
+	}
+	if len(frontier) == 0 || len(interior) == 0 {
+		return false
+	}
+	from := frontier[r.Intn(len(frontier))]
+	to := interior[r.Intn(len(interior))]
+	board.bombs[from] = false
+	board.bombs[to] = true
+	return true
+}
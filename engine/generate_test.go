@@ -0,0 +1,213 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name           string
+		width, height  int
+		bombs          int
+		x, y           int
+		policy         FirstClick
+		freeNeighbours bool
+	}{
+		{"safe", 9, 9, 10, 4, 4, FirstClickSafe, false},
+		{"safe in a corner", 9, 9, 10, 0, 0, FirstClickSafe, false},
+		{"safe on a full board", 9, 9, 80, 8, 8, FirstClickSafe, false},
+		{"opening", 9, 9, 10, 4, 4, FirstClickOpening, true},
+		{"opening in a corner", 9, 9, 10, 0, 0, FirstClickOpening, true},
+		{"opening on an edge", 30, 16, 99, 29, 7, FirstClickOpening, true},
+		{"opening with the most bombs", 9, 9, 72, 4, 4, FirstClickOpening, true},
+		// with more bombs than fit around an opening only the first click is free
+		{"opening falls back to safe", 9, 9, 73, 4, 4, FirstClickOpening, false},
+		{"opening on a full board", 9, 9, 80, 4, 4, FirstClickOpening, false},
+		{"corner", 9, 9, 10, 4, 4, FirstClickCorner, false},
+		{"corner on a full board", 9, 9, 80, 4, 4, FirstClickCorner, false},
+	}
+	for _, test := range tests {
+		for seed := uint64(1); seed <= 20; seed++ {
+			b := Generate(test.width, test.height, test.bombs, seed, test.x, test.y, test.policy)
+			if b.Bombs() != test.bombs {
+				t.Fatalf("%s, seed %d: expected %d bombs, got %d", test.name, seed, test.bombs, b.Bombs())
+			}
+			if b.IsBomb(test.x, test.y) {
+				t.Fatalf("%s, seed %d: expected the first click to be free", test.name, seed)
+			}
+			if test.freeNeighbours && b.Number(test.x, test.y) != 0 {
+				t.Fatalf("%s, seed %d: expected the neighbours of the first click to be free", test.name, seed)
+			}
+		}
+	}
+}
+
+func TestGenerateOpeningFallsBackToSafe(t *testing.T) {
+	for _, bombs := range []int{73, 80} {
+		for seed := uint64(1); seed <= 20; seed++ {
+			opening := Generate(9, 9, bombs, seed, 4, 4, FirstClickOpening)
+			safe := Generate(9, 9, bombs, seed, 4, 4, FirstClickSafe)
+			if !equalBoards(opening, safe) {
+				t.Fatalf("%d bombs, seed %d: expected the board of the safe policy", bombs, seed)
+			}
+		}
+	}
+}
+
+func TestGenerateClassic(t *testing.T) {
+	// on a board with 80 of 81 cells bombs the first click nearly always hits one
+	hits := 0
+	for seed := uint64(1); seed <= 20; seed++ {
+		b := Generate(9, 9, 80, seed, 4, 4, FirstClickClassic)
+		if b.Bombs() != 80 {
+			t.Fatalf("seed %d: expected 80 bombs, got %d", seed, b.Bombs())
+		}
+		if b.IsBomb(4, 4) {
+			hits++
+		}
+	}
+	if hits == 0 {
+		t.Errorf("expected the first click to hit a bomb")
+	}
+}
+
+func TestGenerateCorner(t *testing.T) {
+	for _, bombs := range []int{10, 40, 80} {
+		for seed := uint64(1); seed <= 20; seed++ {
+			classic := Generate(9, 9, bombs, seed, 4, 4, FirstClickClassic)
+			corner := Generate(9, 9, bombs, seed, 4, 4, FirstClickCorner)
+			// the bomb under the first click moves to the first free cell from the top left
+			expected := classic
+			if classic.IsBomb(4, 4) {
+				expected = Generate(9, 9, bombs, seed, 4, 4, FirstClickClassic)
+				for i := range expected.bombs {
+					if !expected.bombs[i] {
+						expected.bombs[i] = true
+						break
+					}
+				}
+				expected.SetBomb(4, 4, false)
+			}
+			if !equalBoards(corner, expected) {
+				t.Fatalf("%d bombs, seed %d: expected the bomb of the first click in the first free cell", bombs, seed)
+			}
+		}
+	}
+}
+
+func TestGenerateNoGuess(t *testing.T) {
+	for _, size := range [][3]int{{9, 9, 10}, {16, 16, 40}} {
+		width, height, bombs := size[0], size[1], size[2]
+		for seed := uint64(1); seed <= 10; seed++ {
+			b, ok := GenerateNoGuess(width, height, bombs, seed, 0, 0, NoGuessAttempts)
+			if !ok {
+				t.Fatalf("%dx%d, seed %d: expected a no-guess board", width, height, seed)
+			}
+			if b.Bombs() != bombs || b.Number(0, 0) != 0 || b.IsBomb(0, 0) {
+				t.Fatalf("%dx%d, seed %d: expected %d bombs and an opening", width, height, seed, bombs)
+			}
+			if _, solved := simulate(b, 0, 0); !solved {
+				t.Fatalf("%dx%d, seed %d: expected the board to be solved without guessing", width, height, seed)
+			}
+			again, _ := GenerateNoGuess(width, height, bombs, seed, 0, 0, NoGuessAttempts)
+			if !equalBoards(b, again) {
+				t.Fatalf("%dx%d, seed %d: expected the same board for the same seed", width, height, seed)
+			}
+		}
+	}
+}
+
+func TestGenerateNoGuessRunsOutOfAttempts(t *testing.T) {
+	// 7 bombs around the first click can only be found by guessing
+	b, ok := GenerateNoGuess(3, 3, 7, 1, 1, 1, 100)
+	if ok {
+		t.Fatalf("expected no no-guess board")
+	}
+	if b.Bombs() != 7 || b.IsBomb(1, 1) {
+		t.Fatalf("expected a board with 7 bombs around the first click")
+	}
+}
+
+func TestCodeGeneratesPinnedBoard(t *testing.T) {
+	tests := []struct {
+		code  string
+		board string
+	}{
+		{"AgkJCgQEKgAN", `
+			mines 9x9 10
+			..*......
+			.*......*
+			........*
+			.........
+			..*......
+			.*.......
+			*.*...*..
+			.........
+			......*..`,
+		},
+		{"AgkJCgQEKgGb", `
+			mines 9x9 10
+			.....*...
+			....*....
+			...*.....
+			..*...*..
+			.*.....*.
+			.........
+			*....*...
+			..*......
+			.........`,
+		},
+		{"AhAQKAAABwHV", `
+			mines 16x16 40
+			......*.........
+			.........*..*.*.
+			*.............*.
+			..........**....
+			..........*..**.
+			*.....*....*....
+			......*.....**..
+			..........*.....
+			.*.....*.......*
+			.*..*.....*.....
+			.......**.....*.
+			....**..........
+			...*....*.*....*
+			.....*.........*
+			....**..........
+			..*.........*..*`,
+		},
+	}
+	for _, test := range tests {
+		code, err := ParseCode(test.code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code.String() != test.code {
+			t.Errorf("expected code %s, got %s", test.code, code)
+		}
+		text, _ := code.Generate().MarshalText()
+		expected, _ := parseBoard(t, test.board).MarshalText()
+		if string(text) != string(expected) {
+			t.Errorf("code %s: expected board\n%s\ngot\n%s", test.code, expected, text)
+		}
+	}
+}
+
+func TestPinnedCodes(t *testing.T) {
+	codes := map[string]Code{
+		"AgkJCgQEKgAN": {Width: 9, Height: 9, Bombs: 10, Seed: 42, X: 4, Y: 4},
+		"AgkJCgQEKgGb": {Width: 9, Height: 9, Bombs: 10, Seed: 42, X: 4, Y: 4, NoGuess: true},
+		"AhAQKAAABwHV": {Width: 16, Height: 16, Bombs: 40, Seed: 7, X: 0, Y: 0, NoGuess: true},
+	}
+	for s, c := range codes {
+		if got := c.String(); got != s {
+			t.Errorf("expected code %s for %+v, got %s", s, c, got)
+		}
+	}
+}
+
+func equalBoards(a, b *Board) bool {
+	textA, _ := a.MarshalText()
+	textB, _ := b.MarshalText()
+	return string(textA) == string(textB)
+}
@@ -0,0 +1,245 @@
+package engine
+
+import (
+	"math"
+)
+
+// component is a group of frontier cells that share constraints, with for
+// every number of bombs k the number of solutions and per cell the number of
+// solutions in which it is a bomb
+type component struct {
+	cells       []int
+	constraints []constraint
+	solutions   []float64
+	bombs       [][]float64
+}
+
+// Probabilities calculates for every covered cell the exact chance that it
+// is a bomb, by enumerating the solutions of independent groups of frontier
+// cells and weighing them by the ways the other bombs fit in the remaining
+// cells. Open cells get -1. Flags are not trusted and are treated as covered.
+// It returns nil when the view is inconsistent.
+func Probabilities(v *View) []float64 {
+	constraints := getConstraints(v, map[int]bool{})
+	components := getComponents(v, constraints)
+	frontier := map[int]bool{}
+	for _, c := range components {
+		for _, cell := range c.cells {
+			frontier[cell] = true
+		}
+	}
+	interior := 0
+	for i, c := range v.cells {
+		if c < 0 && !frontier[i] {
+			interior++
+		}
+	}
+	for _, c := range components {
+		c.enumerate(v.Bombs)
+	}
+	// total is the number of ways to place k bombs in all components
+	total := []float64{1}
+	for _, c := range components {
+		total = convolve(total, c.solutions)
+	}
+	// weight of k bombs in the frontier is relative to the ways the rest fit in the interior
+	weights := make([]float64, len(total))
+	logs := make([]float64, len(total))
+	maxLog := math.Inf(-1)
+	for k := range total {
+		rest := v.Bombs - k
+		if total[k] == 0 || rest < 0 || rest > interior {
+			logs[k] = math.Inf(-1)
+			continue
+		}
+		logs[k] = math.Log(total[k]) + logChoose(interior, rest)
+		maxLog = math.Max(maxLog, logs[k])
+	}
+	if math.IsInf(maxLog, -1) {
+		return nil
+	}
+	sum := 0.0
+	for k := range total {
+		if !math.IsInf(logs[k], -1) {
+			weights[k] = math.Exp(logs[k] - maxLog)
+			sum += weights[k]
+		}
+	}
+	probabilities := make([]float64, len(v.cells))
+	for i, c := range v.cells {
+		if c >= 0 {
+			probabilities[i] = -1
+		}
+	}
+	if interior > 0 {
+		p := 0.0
+		for k := range total {
+			p += weights[k] * float64(v.Bombs-k) / float64(interior)
+		}
+		for i, c := range v.cells {
+			if c < 0 && !frontier[i] {
+				probabilities[i] = p / sum
+			}
+		}
+	}
+	for ci, c := range components {
+		// others is the number of ways to place k bombs in the other components
+		others := []float64{1}
+		for cj, other := range components {
+			if ci != cj {
+				others = convolve(others, other.solutions)
+			}
+		}
+		for j, cell := range c.cells {
+			p := 0.0
+			for k := range c.solutions {
+				if c.solutions[k] == 0 {
+					continue
+				}
+				for r := range others {
+					if k+r < len(weights) && total[k+r] > 0 {
+						p += weights[k+r] * c.bombs[k][j] * others[r] / total[k+r]
+					}
+				}
+			}
+			probabilities[cell] = p / sum
+		}
+	}
+	return probabilities
+}
+
+// getComponents groups the constrained cells that are connected by constraints
+func getComponents(v *View, constraints []constraint) []*component {
+	parent := map[int]int{}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, c := range constraints {
+		for _, cell := range c.cells {
+			if _, ok := parent[cell]; !ok {
+				parent[cell] = cell
+			}
+		}
+		for _, cell := range c.cells[1:] {
+			parent[find(cell)] = find(c.cells[0])
+		}
+	}
+	byRoot := map[int]*component{}
+	components := []*component{}
+	for i := range v.cells {
+		if _, ok := parent[i]; !ok {
+			continue
+		}
+		root := find(i)
+		c, ok := byRoot[root]
+		if !ok {
+			c = &component{}
+			byRoot[root] = c
+			components = append(components, c)
+		}
+		c.cells = append(c.cells, i)
+	}
+	for _, c := range constraints {
+		comp := byRoot[find(c.cells[0])]
+		comp.constraints = append(comp.constraints, c)
+	}
+	return components
+}
+
+// enumerate counts the solutions of the component by backtracking
+func (c *component) enumerate(maxBombs int) {
+	index := map[int]int{}
+	for i, cell := range c.cells {
+		index[cell] = i
+	}
+	// the constraints that every cell is part of
+	of := make([][]int, len(c.cells))
+	for ci, con := range c.constraints {
+		for _, cell := range con.cells {
+			of[index[cell]] = append(of[index[cell]], ci)
+		}
+	}
+	placed := make([]int, len(c.constraints))
+	open := make([]int, len(c.constraints))
+	for ci, con := range c.constraints {
+		open[ci] = len(con.cells)
+	}
+	size := len(c.cells)
+	if maxBombs < size {
+		size = maxBombs
+	}
+	c.solutions = make([]float64, size+1)
+	c.bombs = make([][]float64, size+1)
+	for k := range c.bombs {
+		c.bombs[k] = make([]float64, len(c.cells))
+	}
+	assignment := make([]bool, len(c.cells))
+	var solve func(i, k int)
+	solve = func(i, k int) {
+		if i == len(c.cells) {
+			c.solutions[k]++
+			for j, bomb := range assignment {
+				if bomb {
+					c.bombs[k][j]++
+				}
+			}
+			return
+		}
+		for _, bomb := range []bool{false, true} {
+			if bomb && k+1 > size {
+				continue
+			}
+			ok := true
+			for _, ci := range of[i] {
+				open[ci]--
+				if bomb {
+					placed[ci]++
+				}
+				need := c.constraints[ci].bombs
+				if placed[ci] > need || placed[ci]+open[ci] < need {
+					ok = false
+				}
+			}
+			if ok {
+				assignment[i] = bomb
+				if bomb {
+					solve(i+1, k+1)
+				} else {
+					solve(i+1, k)
+				}
+				assignment[i] = false
+			}
+			for _, ci := range of[i] {
+				open[ci]++
+				if bomb {
+					placed[ci]--
+				}
+			}
+		}
+	}
+	solve(0, 0)
+}
+
+func convolve(a, b []float64) []float64 {
+	result := make([]float64, len(a)+len(b)-1)
+	for i, x := range a {
+		if x == 0 {
+			continue
+		}
+		for j, y := range b {
+			result[i+j] += x * y
+		}
+	}
+	return result
+}
+
+func logChoose(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+// bruteForce calculates the chance that every covered cell is a bomb by
+// trying all ways to place the bombs in the covered cells
+func bruteForce(v *View) []float64 {
+	covered := []int{}
+	for i, c := range v.cells {
+		if c < 0 {
+			covered = append(covered, i)
+		}
+	}
+	bombs := make([]bool, len(v.cells))
+	counts := make([]float64, len(v.cells))
+	solutions := 0.0
+	consistent := func() bool {
+		for i, c := range v.cells {
+			if c < 0 {
+				continue
+			}
+			n := 0
+			v.forEachNeighbour(i, func(j int) {
+				if bombs[j] {
+					n++
+				}
+			})
+			if n != c {
+				return false
+			}
+		}
+		return true
+	}
+	var place func(from, left int)
+	place = func(from, left int) {
+		if left == 0 {
+			if consistent() {
+				solutions++
+				for i, bomb := range bombs {
+					if bomb {
+						counts[i]++
+					}
+				}
+			}
+			return
+		}
+		for j := from; j <= len(covered)-left; j++ {
+			bombs[covered[j]] = true
+			place(j+1, left-1)
+			bombs[covered[j]] = false
+		}
+	}
+	place(0, v.Bombs)
+	if solutions == 0 {
+		return nil
+	}
+	probabilities := make([]float64, len(v.cells))
+	for i, c := range v.cells {
+		if c >= 0 {
+			probabilities[i] = -1
+		} else {
+			probabilities[i] = counts[i] / solutions
+		}
+	}
+	return probabilities
+}
+
+func TestProbabilities(t *testing.T) {
+	for _, size := range [][3]int{{5, 4, 4}, {5, 4, 7}, {6, 3, 5}, {4, 4, 3}} {
+		width, height, bombs := size[0], size[1], size[2]
+		for seed := uint64(1); seed <= 30; seed++ {
+			b := Generate(width, height, bombs, seed, 0, 0, FirstClickSafe)
+			v := NewView(width, height, bombs)
+			v.Reveal(b, 0, 0)
+			// open some more safe cells, so there are several groups of frontier cells
+			for i := int(seed) % 5; i < width*height; i += 5 {
+				if !b.IsBomb(i%width, i/width) && i%3 == 0 {
+					v.Reveal(b, i%width, i/width)
+				}
+			}
+			expected := bruteForce(v)
+			got := Probabilities(v)
+			if len(got) != len(expected) {
+				t.Fatalf("%dx%d, seed %d: expected %d probabilities, got %d", width, height, seed, len(expected), len(got))
+			}
+			for i := range expected {
+				if math.Abs(got[i]-expected[i]) > 1e-9 {
+					t.Fatalf("%dx%d, seed %d: expected %f for row %d, column %d, got %f", width, height, seed, expected[i], i/width+1, i%width+1, got[i])
+				}
+			}
+		}
+	}
+}
+
+func TestProbabilitiesInconsistent(t *testing.T) {
+	// a 2 that can have only 1 bomb around it
+	v := getView(parseBoard(t, `
+		mines 3x3 1
+		oo.
+		o*.
+		...`))
+	v.Set(0, 0, 2)
+	if p := Probabilities(v); p != nil {
+		t.Errorf("expected no probabilities for an inconsistent view, got %v", p)
+	}
+}
+
+func BenchmarkProbabilities(b *testing.B) {
+	views := []*View{}
+	for seed := uint64(1); seed <= 100; seed++ {
+		board := Generate(30, 16, 99, seed, 15, 8, FirstClickOpening)
+		// the view where the solver gets stuck and the player has to guess
+		view, _ := simulate(board, 15, 8)
+		views = append(views, view)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Probabilities(views[i%len(views)])
+	}
+}
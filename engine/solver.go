@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"sort"
+)
+
+// Rules that the solver uses to deduce safe cells and bombs
+const (
+	// RuleSingle: a number sees exactly as many covered cells as it misses bombs, or none
+	RuleSingle = "single"
+	// RuleSubset: the covered cells of one number contain those of another (e.g. the 1-2 pattern)
+	RuleSubset = "subset"
+	// RuleGlobal: the number of bombs left equals the number of covered cells, or zero
+	RuleGlobal = "global"
+)
+
+// Deduction is a cell that is provably safe or a provable bomb
+type Deduction struct {
+	X, Y    int
+	Bomb    bool
+	Rule    string
+	Sources []int
+}
+
+type constraint struct {
+	source int
+	cells  []int
+	bombs  int
+}
+
+// Solve finds all cells that can be deduced from the view without guessing,
+// flags are not trusted and are treated as covered cells
+func Solve(v *View) []Deduction {
+	known := map[int]bool{}
+	deductions := []Deduction{}
+	add := func(cell int, bomb bool, rule string, sources ...int) bool {
+		if _, ok := known[cell]; ok {
+			return false
+		}
+		known[cell] = bomb
+		deductions = append(deductions, Deduction{
+			X:       cell % v.Width,
+			Y:       cell / v.Width,
+			Bomb:    bomb,
+			Rule:    rule,
+			Sources: sources,
+		})
+		return true
+	}
+	for {
+		constraints := getConstraints(v, known)
+		progress := false
+		for _, c := range constraints {
+			if c.bombs == 0 || c.bombs == len(c.cells) {
+				for _, cell := range c.cells {
+					if add(cell, c.bombs > 0, RuleSingle, c.source) {
+						progress = true
+					}
+				}
+			}
+		}
+		if progress {
+			continue
+		}
+		for _, a := range constraints {
+			for _, b := range constraints {
+				if a.source == b.source || !overlaps(a.cells, b.cells) {
+					continue
+				}
+				onlyA, onlyB := difference(a.cells, b.cells), difference(b.cells, a.cells)
+				// b has so many more bombs than a that all cells only b sees are bombs
+				if b.bombs-a.bombs == len(onlyB) && len(onlyB) > 0 {
+					for _, cell := range onlyB {
+						if add(cell, true, RuleSubset, a.source, b.source) {
+							progress = true
+						}
+					}
+					for _, cell := range onlyA {
+						if add(cell, false, RuleSubset, a.source, b.source) {
+							progress = true
+						}
+					}
+				}
+				// the cells of a are all seen by b and contain all of b's bombs
+				if len(onlyA) == 0 && a.bombs == b.bombs {
+					for _, cell := range onlyB {
+						if add(cell, false, RuleSubset, a.source, b.source) {
+							progress = true
+						}
+					}
+				}
+			}
+		}
+		if progress {
+			continue
+		}
+		unknown, bombs := []int{}, v.Bombs
+		for i, c := range v.cells {
+			if c >= 0 {
+				continue
+			}
+			bomb, ok := known[i]
+			if !ok {
+				unknown = append(unknown, i)
+			} else if bomb {
+				bombs--
+			}
+		}
+		if len(unknown) > 0 && (bombs == 0 || bombs == len(unknown)) {
+			for _, cell := range unknown {
+				add(cell, bombs > 0, RuleGlobal)
+			}
+			progress = true
+		}
+		if !progress {
+			return deductions
+		}
+	}
+}
+
+func getConstraints(v *View, known map[int]bool) []constraint {
+	constraints := []constraint{}
+	for i, n := range v.cells {
+		if n <= 0 {
+			continue
+		}
+		c := constraint{source: i, cells: []int{}, bombs: n}
+		v.forEachNeighbour(i, func(j int) {
+			if v.cells[j] >= 0 {
+				return
+			}
+			bomb, ok := known[j]
+			if !ok {
+				c.cells = append(c.cells, j)
+			} else if bomb {
+				c.bombs--
+			}
+		})
+		if len(c.cells) > 0 {
+			sort.Ints(c.cells)
+			constraints = append(constraints, c)
+		}
+	}
+	return constraints
+}
+
+func overlaps(a, b []int) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// difference gets the cells in a that are not in b
+func difference(a, b []int) []int {
+	result := []int{}
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if x == y {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, x)
+		}
+	}
+	return result
+}
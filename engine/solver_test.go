@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// parseBoard reads a board in the text format, the rows may be indented
+func parseBoard(t *testing.T, text string) *Board {
+	t.Helper()
+	b := &Board{}
+	if err := b.UnmarshalText([]byte(text)); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// getView gets what a player sees of the board: the open cells and the flags
+func getView(b *Board) *View {
+	v := NewView(b.Width, b.Height, b.Bombs())
+	for y := 0; y < b.Height; y++ {
+		for x := 0; x < b.Width; x++ {
+			switch {
+			case b.IsFlagged(x, y):
+				v.Set(x, y, Flagged)
+			case b.IsOpen(x, y):
+				v.Set(x, y, b.Number(x, y))
+			}
+		}
+	}
+	return v
+}
+
+// formatDeductions writes the deductions as sorted "x,y bomb/safe rule" lines
+func formatDeductions(deductions []Deduction) string {
+	lines := []string{}
+	for _, d := range deductions {
+		kind := "safe"
+		if d.Bomb {
+			kind = "bomb"
+		}
+		lines = append(lines, string(rune('0'+d.X))+","+string(rune('0'+d.Y))+" "+kind+" "+d.Rule)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		board    string
+		expected string
+	}{
+		{"single bomb", `
+			mines 3x3 1
+			111
+			1*1
+			111`,
+			"1,1 bomb single",
+		},
+		{"flags are not trusted", `
+			mines 3x3 1
+			111
+			1F1
+			111`,
+			"1,1 bomb single",
+		},
+		{"single safe", `
+			mines 3x2 1
+			1*.
+			11.`,
+			"1,0 bomb single\n2,0 safe single\n2,1 safe single",
+		},
+		{"one-two-one pattern", `
+			mines 3x2 2
+			*.*
+			121`,
+			"0,0 bomb subset\n1,0 safe single\n2,0 bomb subset",
+		},
+		{"global count", `
+			mines 5x1 1
+			1*...`,
+			"1,0 bomb single\n2,0 safe global\n3,0 safe global\n4,0 safe global",
+		},
+		{"fifty-fifty", `
+			mines 3x2 1
+			01*
+			01.`,
+			"",
+		},
+	}
+	for _, test := range tests {
+		b := parseBoard(t, test.board)
+		got := formatDeductions(Solve(getView(b)))
+		if got != test.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestSolveIsCorrect(t *testing.T) {
+	for seed := uint64(1); seed <= 20; seed++ {
+		b := Generate(16, 16, 40, seed, 8, 8, FirstClickOpening)
+		v := getView(b)
+		v.Reveal(b, 8, 8)
+		for _, d := range Solve(v) {
+			if d.Bomb != b.IsBomb(d.X, d.Y) {
+				t.Fatalf("seed %d: wrong deduction %+v", seed, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package engine
+
+// Clicks counts the clicks of a game by kind, a chord is a click on an open
+// cell that opens its neighbours
+type Clicks struct {
+	Left  int `json:"left"`
+	Right int `json:"right"`
+	Chord int `json:"chord"`
+}
+
+// Total counts all clicks
+func (c Clicks) Total() int {
+	return c.Left + c.Right + c.Chord
+}
+
+// Stats are the standard metrics of a game
+type Stats struct {
+	BBBV         int    `json:"3bv"`
+	Solved       int    `json:"3bvSolved"`
+	ZiNi         int    `json:"zini"`
+	HumanZiNi    int    `json:"humanZini"`
+	Clicks       Clicks `json:"clicks"`
+	Milliseconds int64  `json:"milliseconds"`
+	// Hints is the number of hints that the player used, the game sets it
+	Hints int `json:"hints"`
+}
+
+// NewStats calculates the metrics of a game from the board, what was opened of
+// it, the clicks and the time
+func NewStats(b *Board, v *View, clicks Clicks, milliseconds int64) Stats {
+	return Stats{
+		BBBV:         BBBV(b),
+		Solved:       BBBVSolved(b, v),
+		ZiNi:         ZiNi(b),
+		HumanZiNi:    HumanZiNi(b),
+		Clicks:       clicks,
+		Milliseconds: milliseconds,
+	}
+}
+
+// Seconds gets the time of the game in seconds
+func (s Stats) Seconds() float64 {
+	return float64(s.Milliseconds) / 1000
+}
+
+// BBBVPerSecond gets the 3BV solved per second
+func (s Stats) BBBVPerSecond() float64 {
+	if s.Milliseconds <= 0 {
+		return 0
+	}
+	return float64(s.Solved) / s.Seconds()
+}
+
+// IOE gets the index of efficiency: the 3BV solved per click
+func (s Stats) IOE() float64 {
+	if s.Clicks.Total() == 0 {
+		return 0
+	}
+	return float64(s.Solved) / float64(s.Clicks.Total())
+}
+
+// Efficiency gets the IOE as a percentage
+func (s Stats) Efficiency() float64 {
+	return s.IOE() * 100
+}
+
+// RQP gets the rapport qualité prix: the time plus one divided by the 3BV/s
+// (lower is better)
+func (s Stats) RQP() float64 {
+	if s.Solved == 0 {
+		return 0
+	}
+	return (s.Seconds() + 1) / s.BBBVPerSecond()
+}
+
+// getOpenings labels the cells with the opening they belong to (from 1), an
+// opening is a region of empty cells with the numbers around it, numbers on
+// the border of two openings get the label of the first
+func getOpenings(b *Board) ([]int, int) {
+	labels := make([]int, b.Width*b.Height)
+	count := 0
+	for i := range labels {
+		x, y := i%b.Width, i/b.Width
+		if labels[i] != 0 || b.IsBomb(x, y) || b.Number(x, y) != 0 {
+			continue
+		}
+		count++
+		labels[i] = count
+		stack := []int{i}
+		for len(stack) > 0 {
+			j := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			b.ForEachNeighbour(j%b.Width, j/b.Width, func(x, y int) {
+				k := y*b.Width + x
+				if labels[k] != 0 {
+					return
+				}
+				labels[k] = count
+				if b.Number(x, y) == 0 {
+					stack = append(stack, k)
+				}
+			})
+		}
+	}
+	return labels, count
+}
+
+// BBBV counts the minimum number of left clicks that clear the board (3BV):
+// one for every opening and one for every number outside the openings
+func BBBV(b *Board) int {
+	labels, count := getOpenings(b)
+	for i, label := range labels {
+		if label == 0 && !b.IsBomb(i%b.Width, i/b.Width) {
+			count++
+		}
+	}
+	return count
+}
+
+// BBBVSolved counts the part of the 3BV that is open in the view: openings of
+// which all empty cells are open and open numbers outside the openings
+func BBBVSolved(b *Board, v *View) int {
+	labels, count := getOpenings(b)
+	solved := make([]bool, count+1)
+	for i := 1; i <= count; i++ {
+		solved[i] = true
+	}
+	n := 0
+	for i, label := range labels {
+		x, y := i%b.Width, i/b.Width
+		switch {
+		case label == 0:
+			if !b.IsBomb(x, y) && v.IsOpen(x, y) {
+				n++
+			}
+		case b.Number(x, y) == 0 && !v.IsOpen(x, y):
+			solved[label] = false
+		}
+	}
+	for i := 1; i <= count; i++ {
+		if solved[i] {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBBBV(t *testing.T) {
+	tests := []struct {
+		name  string
+		board string
+		bbbv  int
+	}{
+		{"no mines is one opening", `
+			mines 3x3 0
+			...
+			...
+			...`, 1},
+		{"only numbers", `
+			mines 3x3 1
+			...
+			.*.
+			...`, 8},
+		{"numbers around an opening", `
+			mines 4x4 1
+			*...
+			....
+			....
+			....`, 1},
+		{"two openings", `
+			mines 5x1 1
+			..*..`, 2},
+		{"openings and a number between mines", `
+			mines 7x1 2
+			..*.*..`, 3},
+		{"two openings with all numbers next to them", `
+			mines 5x5 3
+			*....
+			.....
+			..*..
+			.....
+			....*`, 2},
+		{"two openings and two numbers in a corner", `
+			mines 4x4 3
+			.*..
+			*...
+			....
+			...*`, 4},
+	}
+	for _, test := range tests {
+		if got := BBBV(parseBoard(t, test.board)); got != test.bbbv {
+			t.Errorf("%s: expected 3BV %d, got %d", test.name, test.bbbv, got)
+		}
+	}
+}
+
+func TestBBBVSolved(t *testing.T) {
+	b := parseBoard(t, `
+		mines 7x1 2
+		..*.*..`)
+	v := getView(b)
+	if got := BBBVSolved(b, v); got != 0 {
+		t.Errorf("expected 0 solved, got %d", got)
+	}
+	v.Reveal(b, 0, 0)
+	if got := BBBVSolved(b, v); got != 1 {
+		t.Errorf("expected the left opening solved, got %d", got)
+	}
+	v.Reveal(b, 3, 0)
+	v.Reveal(b, 6, 0)
+	if got := BBBVSolved(b, v); got != 3 {
+		t.Errorf("expected all 3 solved, got %d", got)
+	}
+}
+
+func TestStats(t *testing.T) {
+	s := Stats{BBBV: 12, Solved: 10, Clicks: Clicks{Left: 15, Right: 3, Chord: 2}, Milliseconds: 5000}
+	tests := []struct {
+		name     string
+		value    float64
+		expected float64
+	}{
+		{"seconds", s.Seconds(), 5},
+		{"3BV/s", s.BBBVPerSecond(), 2},
+		{"IOE", s.IOE(), 0.5},
+		{"efficiency", s.Efficiency(), 50},
+		{"RQP", s.RQP(), 3},
+	}
+	for _, test := range tests {
+		if math.Abs(test.value-test.expected) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, test.value)
+		}
+	}
+	if s.Clicks.Total() != 20 {
+		t.Errorf("expected 20 clicks, got %d", s.Clicks.Total())
+	}
+	// nothing solved, no clicks and no time do not divide by zero
+	zero := Stats{}
+	if zero.BBBVPerSecond() != 0 || zero.IOE() != 0 || zero.RQP() != 0 {
+		t.Errorf("expected zero metrics for an empty game")
+	}
+}
+
+func TestNewStats(t *testing.T) {
+	b := parseBoard(t, `
+		mines 5x1 1
+		..*..`)
+	v := getView(b)
+	v.Reveal(b, 0, 0)
+	s := NewStats(b, v, Clicks{Left: 1}, 1500)
+	if s.BBBV != 2 || s.Solved != 1 || s.Milliseconds != 1500 || s.IOE() != 1 {
+		t.Errorf("expected 3BV 2 with 1 solved in 1 click, got %+v", s)
+	}
+}
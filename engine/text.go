@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Characters of the cells in the text format of a board
+const (
+	TextCovered       = '.'
+	TextMine          = '*'
+	TextOpen          = 'o'
+	TextOpenMine      = 'X'
+	TextFlagged       = 'F'
+	TextWrongFlag     = 'f'
+	TextQuestion      = 'Q'
+	TextWrongQuestion = 'q'
+	textHeaderStart   = "mines"
+)
+
+// MarshalText writes the board in the text format: a header line with
+// "mines", the size, the number of mines and the options (e.g.
+// "mines 9x9 10 questions"), followed by a line per row with a character per
+// cell: "." covered, "*" mine, "0"-"8" open, "X" open mine, "F" flagged mine,
+// "f" flagged without a mine, "Q" question marked mine and "q" question mark
+// without a mine
+func (b *Board) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	header := []string{textHeaderStart, strconv.Itoa(b.Width) + "x" + strconv.Itoa(b.Height), strconv.Itoa(b.Bombs())}
+	buf.WriteString(strings.Join(append(header, b.Options...), " ") + "\n")
+	for y := 0; y < b.Height; y++ {
+		for x := 0; x < b.Width; x++ {
+			var c byte
+			switch {
+			case b.IsFlagged(x, y) && b.IsBomb(x, y):
+				c = TextFlagged
+			case b.IsFlagged(x, y):
+				c = TextWrongFlag
+			case b.IsQuestion(x, y) && b.IsBomb(x, y):
+				c = TextQuestion
+			case b.IsQuestion(x, y):
+				c = TextWrongQuestion
+			case b.IsOpen(x, y) && b.IsBomb(x, y):
+				c = TextOpenMine
+			case b.IsBomb(x, y):
+				c = TextMine
+			case b.IsOpen(x, y):
+				c = byte('0' + b.Number(x, y))
+			default:
+				c = TextCovered
+			}
+			buf.WriteByte(c)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText reads a board in the text format (see MarshalText), lines
+// starting with "#" are comments, open cells may be "o" instead of their
+// number and numbers must match the mines
+func (b *Board) UnmarshalText(text []byte) error {
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("UnmarshalText: missing header")
+	}
+	header := strings.Fields(lines[0])
+	if len(header) < 3 || header[0] != textHeaderStart {
+		return fmt.Errorf("UnmarshalText: invalid header '%s'", lines[0])
+	}
+	var width, height, mines int
+	if _, err := fmt.Sscanf(header[1]+" "+header[2], "%dx%d %d", &width, &height, &mines); err != nil || width <= 0 || height <= 0 {
+		return fmt.Errorf("UnmarshalText: invalid header '%s'", lines[0])
+	}
+	if len(lines)-1 != height {
+		return fmt.Errorf("UnmarshalText: expected %d rows, got %d", height, len(lines)-1)
+	}
+	board := NewBoard(width, height)
+	board.Options = append(board.Options, header[3:]...)
+	numbers := map[int]int{}
+	for y, row := range lines[1:] {
+		if len(row) != width {
+			return fmt.Errorf("UnmarshalText: expected %d cells in row %d, got %d", width, y+1, len(row))
+		}
+		for x := 0; x < width; x++ {
+			switch c := row[x]; {
+			case c == TextCovered:
+			case c == TextMine:
+				board.SetBomb(x, y, true)
+			case c == TextOpen:
+				board.SetOpen(x, y, true)
+			case c == TextOpenMine:
+				board.SetBomb(x, y, true)
+				board.SetOpen(x, y, true)
+			case c >= '0' && c <= '8':
+				board.SetOpen(x, y, true)
+				numbers[y*width+x] = int(c - '0')
+			case c == TextFlagged:
+				board.SetBomb(x, y, true)
+				board.SetFlagged(x, y, true)
+			case c == TextWrongFlag:
+				board.SetFlagged(x, y, true)
+			case c == TextQuestion:
+				board.SetBomb(x, y, true)
+				board.SetQuestion(x, y, true)
+			case c == TextWrongQuestion:
+				board.SetQuestion(x, y, true)
+			default:
+				return fmt.Errorf("UnmarshalText: invalid cell '%c' in row %d", c, y+1)
+			}
+		}
+	}
+	if board.Bombs() != mines {
+		return fmt.Errorf("UnmarshalText: expected %d mines, got %d", mines, board.Bombs())
+	}
+	for i, n := range numbers {
+		if board.Number(i%width, i/width) != n {
+			return fmt.Errorf("UnmarshalText: number %d in row %d does not match the mines", n, i/width+1)
+		}
+	}
+	*b = *board
+	return nil
+}
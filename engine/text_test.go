@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTextRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"covered", "mines 3x3 1\n...\n.*.\n...\n"},
+		{"open", "mines 3x3 1\n111\n1*1\n111\n"},
+		{"open mine", "mines 3x3 1\n111\n1X1\n111\n"},
+		{"flagged", "mines 3x3 2\n1F.\n12f\n..*\n"},
+		{"question", "mines 3x3 2\n1Q.\n12q\n..*\n"},
+		{"options", "mines 3x3 1 questions practice\n.q.\n.*.\n...\n"},
+	}
+	for _, test := range tests {
+		b := &Board{}
+		if err := b.UnmarshalText([]byte(test.text)); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		text, err := b.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(text) != test.text {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.text, text)
+		}
+	}
+}
+
+func TestUnmarshalText(t *testing.T) {
+	text := `
+		# a hand written board
+		mines 4x3 2 questions
+
+		# the rows
+		0o2*
+		01F.
+		0o1q
+	`
+	b := &Board{}
+	if err := b.UnmarshalText([]byte(text)); err != nil {
+		t.Fatal(err)
+	}
+	if b.Width != 4 || b.Height != 3 || b.Bombs() != 2 || !b.HasOption("questions") || b.HasOption("practice") {
+		t.Fatalf("expected a 4x3 board with 2 mines and questions, got %+v", b)
+	}
+	if !b.IsOpen(1, 0) || b.Number(1, 0) != 1 || !b.IsOpen(2, 2) || b.IsOpen(3, 0) {
+		t.Errorf("expected the open cells")
+	}
+	if !b.IsFlagged(2, 1) || !b.IsBomb(2, 1) || !b.IsBomb(3, 0) || b.IsFlagged(3, 0) {
+		t.Errorf("expected the flagged mine and the covered mine")
+	}
+	if !b.IsQuestion(3, 2) || b.IsBomb(3, 2) {
+		t.Errorf("expected the question mark without a mine")
+	}
+}
+
+func TestUnmarshalTextRejectsInvalidBoards(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		error string
+	}{
+		{"empty", "# nothing\n", "missing header"},
+		{"header", "board 3x3 1\n...\n.*.\n...\n", "invalid header"},
+		{"size", "mines 3by3 1\n...\n.*.\n...\n", "invalid header"},
+		{"negative size", "mines -3x3 1\n...\n.*.\n...\n", "invalid header"},
+		{"too few mines", "mines 3x3 2\n...\n.*.\n...\n", "expected 2 mines, got 1"},
+		{"too many mines", "mines 3x3 1\n..*\n.*.\n...\n", "expected 1 mines, got 2"},
+		{"number without mine", "mines 3x3 1\n1..\n...\n..*\n", "number 1 in row 1 does not match"},
+		{"number with too few", "mines 3x3 2\n*..\n.1.\n..*\n", "number 1 in row 2 does not match"},
+		{"short row", "mines 3x3 1\n...\n.*\n...\n", "expected 3 cells in row 2, got 2"},
+		{"long row", "mines 3x3 1\n....\n.*.\n...\n", "expected 3 cells in row 1, got 4"},
+		{"missing row", "mines 3x3 1\n...\n.*.\n", "expected 3 rows, got 2"},
+		{"extra row", "mines 3x3 1\n...\n.*.\n...\n...\n", "expected 3 rows, got 4"},
+		{"invalid cell", "mines 3x3 1\n...\n.*.\n..9\n", "invalid cell '9' in row 3"},
+	}
+	for _, test := range tests {
+		b := &Board{}
+		err := b.UnmarshalText([]byte(test.text))
+		if err == nil || !strings.Contains(err.Error(), test.error) {
+			t.Errorf("%s: expected error '%s', got %v", test.name, test.error, err)
+		}
+	}
+}
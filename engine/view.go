@@ -0,0 +1,96 @@
+package engine
+
+// Cell states in a view, open cells have their number (0-8)
+const (
+	Covered = -1
+	Flagged = -2
+)
+
+// View is what a player can see of a board: the numbers of the open cells
+// and which cells are covered or flagged
+type View struct {
+	Width  int
+	Height int
+	Bombs  int
+	cells  []int
+}
+
+// NewView creates a new view with all cells covered
+func NewView(width, height, bombs int) *View {
+	v := &View{
+		Width:  width,
+		Height: height,
+		Bombs:  bombs,
+		cells:  make([]int, width*height),
+	}
+	for i := range v.cells {
+		v.cells[i] = Covered
+	}
+	return v
+}
+
+// Get gets the state of the cell (Covered, Flagged or its number)
+func (v *View) Get(x, y int) int {
+	return v.cells[y*v.Width+x]
+}
+
+// Set sets the state of the cell (Covered, Flagged or its number)
+func (v *View) Set(x, y, state int) {
+	v.cells[y*v.Width+x] = state
+}
+
+// IsOpen returns whether or not the cell is open
+func (v *View) IsOpen(x, y int) bool {
+	return v.Get(x, y) >= 0
+}
+
+// Reveal opens the cell using the board and opens the neighbours of empty
+// cells, it returns false when the cell is a bomb
+func (v *View) Reveal(b *Board, x, y int) bool {
+	if b.IsBomb(x, y) {
+		return false
+	}
+	stack := []int{y*v.Width + x}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if v.cells[i] >= 0 {
+			continue
+		}
+		cx, cy := i%v.Width, i/v.Width
+		v.cells[i] = b.Number(cx, cy)
+		if v.cells[i] == 0 {
+			b.ForEachNeighbour(cx, cy, func(nx, ny int) {
+				if v.cells[ny*v.Width+nx] == Covered {
+					stack = append(stack, ny*v.Width+nx)
+				}
+			})
+		}
+	}
+	return true
+}
+
+// Covered counts the cells that are not open (including the flagged cells)
+func (v *View) Covered() int {
+	n := 0
+	for _, c := range v.cells {
+		if c < 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (v *View) forEachNeighbour(i int, do func(j int)) {
+	x, y := i%v.Width, i/v.Width
+	for d := 0; d < 9; d++ {
+		dy, dx := d/3-1, d%3-1
+		if dy == 0 && dx == 0 {
+			continue
+		}
+		if y+dy < 0 || x+dx < 0 || y+dy >= v.Height || x+dx >= v.Width {
+			continue
+		}
+		do((y+dy)*v.Width + x + dx)
+	}
+}
@@ -0,0 +1,216 @@
+package engine
+
+// clearing is the state of a board that is cleared with the least clicks,
+// without mistakes: only bombs are flagged
+type clearing struct {
+	board    *Board
+	numbers  []int
+	openings []int   // opening of each empty cell, -1 for other cells
+	cells    [][]int // cells of each opening, including the numbers around it
+	exposed  []bool  // numbers that are not next to an empty cell
+	open     []bool
+	flagged  []bool
+	opened   []bool
+	clicks   int
+}
+
+func newClearing(b *Board) *clearing {
+	n := b.Width * b.Height
+	c := &clearing{
+		board:    b,
+		numbers:  make([]int, n),
+		openings: make([]int, n),
+		exposed:  make([]bool, n),
+		open:     make([]bool, n),
+		flagged:  make([]bool, n),
+	}
+	for i := 0; i < n; i++ {
+		c.numbers[i] = b.Number(i%b.Width, i/b.Width)
+		c.openings[i] = -1
+	}
+	for i := 0; i < n; i++ {
+		if c.openings[i] >= 0 || c.isBomb(i) || c.numbers[i] != 0 {
+			continue
+		}
+		id := len(c.cells)
+		cells := []int{}
+		seen := map[int]bool{i: true}
+		stack := []int{i}
+		for len(stack) > 0 {
+			j := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			cells = append(cells, j)
+			if c.numbers[j] != 0 {
+				continue
+			}
+			c.openings[j] = id
+			c.forEachNeighbour(j, func(k int) {
+				if !seen[k] {
+					seen[k] = true
+					stack = append(stack, k)
+				}
+			})
+		}
+		c.cells = append(c.cells, cells)
+	}
+	c.opened = make([]bool, len(c.cells))
+	for i := 0; i < n; i++ {
+		if c.isBomb(i) || c.numbers[i] == 0 {
+			continue
+		}
+		c.exposed[i] = true
+		c.forEachNeighbour(i, func(j int) {
+			if !c.isBomb(j) && c.numbers[j] == 0 {
+				c.exposed[i] = false
+			}
+		})
+	}
+	return c
+}
+
+func (c *clearing) isBomb(i int) bool {
+	return c.board.bombs[i]
+}
+
+func (c *clearing) forEachNeighbour(i int, do func(j int)) {
+	c.board.ForEachNeighbour(i%c.board.Width, i/c.board.Width, func(x, y int) {
+		do(y*c.board.Width + x)
+	})
+}
+
+// reveal opens a cell, an empty cell opens its opening
+func (c *clearing) reveal(i int) {
+	if c.openings[i] < 0 {
+		c.open[i] = true
+		return
+	}
+	id := c.openings[i]
+	if c.opened[id] {
+		return
+	}
+	c.opened[id] = true
+	for _, j := range c.cells[id] {
+		c.open[j] = true
+	}
+}
+
+// click opens a cell with a left click
+func (c *clearing) click(i int) {
+	c.clicks++
+	c.reveal(i)
+}
+
+// premium counts the clicks saved by opening the number (when closed),
+// flagging the bombs around it and chording it, instead of clicking the
+// cells that the chord opens
+func (c *clearing) premium(i int) int {
+	if c.isBomb(i) || c.numbers[i] == 0 {
+		return 0
+	}
+	saved, cost := 0, 1
+	if !c.open[i] {
+		cost++
+		if c.exposed[i] {
+			saved++
+		}
+	}
+	openings := map[int]bool{}
+	c.forEachNeighbour(i, func(j int) {
+		switch {
+		case c.isBomb(j):
+			if !c.flagged[j] {
+				cost++
+			}
+		case c.open[j]:
+		case c.openings[j] >= 0:
+			if !c.opened[c.openings[j]] {
+				openings[c.openings[j]] = true
+			}
+		case c.exposed[j]:
+			saved++
+		}
+	})
+	return saved + len(openings) - cost
+}
+
+// chord opens the number (when closed), flags the bombs around it and chords it
+func (c *clearing) chord(i int) {
+	if !c.open[i] {
+		c.click(i)
+	}
+	c.forEachNeighbour(i, func(j int) {
+		if c.isBomb(j) && !c.flagged[j] {
+			c.flagged[j] = true
+			c.clicks++
+		}
+	})
+	c.clicks++
+	c.forEachNeighbour(i, func(j int) {
+		if !c.isBomb(j) {
+			c.reveal(j)
+		}
+	})
+}
+
+// openOpenings clicks every opening that is still closed
+func (c *clearing) openOpenings() {
+	for id, cells := range c.cells {
+		if !c.opened[id] {
+			c.click(cells[0])
+		}
+	}
+}
+
+// finish clicks every exposed number that is still closed and counts the clicks
+func (c *clearing) finish() int {
+	c.openOpenings()
+	for i, exposed := range c.exposed {
+		if exposed && !c.open[i] {
+			c.click(i)
+		}
+	}
+	return c.clicks
+}
+
+// ZiNi estimates the least clicks that clear the board with flags and chords:
+// it clicks all openings and then keeps chording the number that saves the
+// most clicks (the first one on ties), the rest of the board is clicked
+func ZiNi(b *Board) int {
+	c := newClearing(b)
+	c.openOpenings()
+	for {
+		best, max := -1, 0
+		for i := range c.numbers {
+			if p := c.premium(i); p > max {
+				best, max = i, p
+			}
+		}
+		if best < 0 {
+			break
+		}
+		c.chord(best)
+	}
+	return c.finish()
+}
+
+// HumanZiNi estimates the clicks of a player that clears the board from the
+// top left to the bottom right: clicking each closed opening and chording
+// each number that saves clicks, or else clicking it when it is not next to
+// an opening
+func HumanZiNi(b *Board) int {
+	c := newClearing(b)
+	for i := range c.numbers {
+		switch {
+		case c.isBomb(i):
+		case c.openings[i] >= 0:
+			if !c.opened[c.openings[i]] {
+				c.click(i)
+			}
+		case c.premium(i) > 0:
+			c.chord(i)
+		case c.exposed[i] && !c.open[i]:
+			c.click(i)
+		}
+	}
+	return c.finish()
+}
@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestZiNi(t *testing.T) {
+	tests := []struct {
+		name      string
+		board     string
+		bbbv      int
+		zini      int
+		humanZiNi int
+	}{
+		// one click opens the board
+		{"no mines", `
+			mines 3x3 0
+			...
+			...
+			...`, 1, 1, 1},
+		// click the top number, flag the mine and chord it to open the cells
+		// around it, then click and chord the bottom number to open the rest,
+		// human ZiNi clicks the top left corner first
+		{"a ring of numbers", `
+			mines 3x3 1
+			...
+			.*.
+			...`, 8, 5, 6},
+		// chording the number between the mines costs more than clicking it
+		{"openings and a number between mines", `
+			mines 7x1 2
+			..*.*..`, 3, 3, 3},
+		// flagging both mines to chord the 2 costs as much as clicking all numbers
+		{"a number above a row of cells", `
+			mines 3x2 2
+			*2*
+			...`, 4, 4, 4},
+	}
+	for _, test := range tests {
+		b := parseBoard(t, test.board)
+		if got := BBBV(b); got != test.bbbv {
+			t.Errorf("%s: expected 3BV %d, got %d", test.name, test.bbbv, got)
+		}
+		if got := ZiNi(b); got != test.zini {
+			t.Errorf("%s: expected ZiNi %d, got %d", test.name, test.zini, got)
+		}
+		if got := HumanZiNi(b); got != test.humanZiNi {
+			t.Errorf("%s: expected human ZiNi %d, got %d", test.name, test.humanZiNi, got)
+		}
+	}
+}
+
+func TestZiNiSavesClicks(t *testing.T) {
+	for seed := uint64(1); seed <= 50; seed++ {
+		b := Generate(30, 16, 99, seed, 0, 0, FirstClickClassic)
+		bbbv, zini, human := BBBV(b), ZiNi(b), HumanZiNi(b)
+		if zini <= 0 || zini > bbbv || human > bbbv {
+			t.Fatalf("seed %d: expected 0 < ZiNi %d and human ZiNi %d <= 3BV %d", seed, zini, human, bbbv)
+		}
+	}
+}
+
+func BenchmarkZiNi(b *testing.B) {
+	boards := []*Board{}
+	for seed := uint64(1); seed <= 100; seed++ {
+		boards = append(boards, Generate(30, 16, 99, seed, 0, 0, FirstClickClassic))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ZiNi(boards[i%len(boards)])
+	}
+}
+
+func BenchmarkHumanZiNi(b *testing.B) {
+	boards := []*Board{}
+	for seed := uint64(1); seed <= 100; seed++ {
+		boards = append(boards, Generate(30, 16, 99, seed, 0, 0, FirstClickClassic))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		HumanZiNi(boards[i%len(boards)])
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"fmt"
+)
+
+// Bus dispatches named events to subscribers and calls registered actions
+type Bus struct {
+	subscribers map[string][]func()
+	actions     map[string]func(args ...interface{})
+}
+
+// New creates a new event bus
+func New() *Bus {
+	return &Bus{
+		subscribers: map[string][]func(){},
+		actions:     map[string]func(args ...interface{}){},
+	}
+}
+
+// Subscribe adds a function that is called when the event is emitted
+func (b *Bus) Subscribe(event string, fn func()) {
+	b.subscribers[event] = append(b.subscribers[event], fn)
+}
+
+// Emit calls the subscribers of the event
+func (b *Bus) Emit(event string) {
+	for _, fn := range b.subscribers[event] {
+		fn()
+	}
+}
+
+// RegisterAction registers a function that can be called by name
+func (b *Bus) RegisterAction(name string, fn func(args ...interface{})) {
+	b.actions[name] = fn
+}
+
+// Call calls a registered action
+func (b *Bus) Call(name string, args ...interface{}) error {
+	fn, ok := b.actions[name]
+	if !ok {
+		return fmt.Errorf("Call: action '%s' not found", name)
+	}
+	fn(args...)
+	return nil
+}
+
+// Functions gets the functions that expressions can use to emit events and call actions
+func (b *Bus) Functions() map[string]interface{} {
+	return map[string]interface{}{
+		"emit": func(event string) bool {
+			b.Emit(event)
+			return true
+		},
+		"call": func(name string, args ...interface{}) (bool, error) {
+			err := b.Call(name, args...)
+			return err == nil, err
+		},
+	}
+}
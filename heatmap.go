@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strconv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/mevdschee/raylib-go-mines/engine"
+)
+
+const (
+	heatmapOff = iota
+	heatmapTint
+	heatmapPercentages
+)
+
+type heatmap struct {
+	mode          int
+	closed        int
+	probabilities []float64
+}
+
+// toggleHeatmap cycles between no heatmap, tinted tiles and tinted tiles with percentages
+func (g *game) toggleHeatmap() {
+	g.heatmap.mode = (g.heatmap.mode + 1) % 3
+	g.heatmap.probabilities = nil
+}
+
+func (g *game) drawHeatmap(scale int) {
+	if g.heatmap.mode == heatmapOff || g.state == stateWon || g.state == stateLost {
+		return
+	}
+	if g.state == statePlaying {
+		// the probabilities are help, the game no longer counts for the high scores
+		g.assisted = true
+	}
+	// the probabilities only change when tiles are opened
+	if g.heatmap.probabilities == nil || g.heatmap.closed != g.closed {
+		g.heatmap.probabilities = engine.Probabilities(g.getView())
+		g.heatmap.closed = g.closed
+	}
+	if g.heatmap.probabilities == nil {
+		return
+	}
+	s := int32(scale)
+	for y := 0; y < g.c.height; y++ {
+		for x := 0; x < g.c.width; x++ {
+			p := g.heatmap.probabilities[y*g.c.width+x]
+			if p < 0 {
+				continue
+			}
+			px, py := int32(12+x*16)*s, int32(55+y*16)*s
+			color := rl.NewColor(uint8(255*p), uint8(255*(1-p)), 0, 110)
+			rl.DrawRectangle(px, py, 16*s, 16*s, color)
+			if g.heatmap.mode == heatmapPercentages {
+				text := strconv.Itoa(int(p*100 + 0.5))
+				fontSize := 5 * s
+				tx := px + (16*s-rl.MeasureText(text, fontSize))/2
+				rl.DrawText(text, tx, py+(16*s-fontSize)/2, fontSize, rl.Black)
+			}
+		}
+	}
+}
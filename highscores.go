@@ -0,0 +1,95 @@
+package main
+
+import (
+	"log"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	gui "github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/mevdschee/raylib-go-mines/scores"
+)
+
+// getUserName gets the name of the user of the computer as the default player name
+func getUserName() string {
+	u, err := user.Current()
+	if err != nil || u.Username == "" {
+		return "Player"
+	}
+	// on Windows the name has the domain (e.g. "DOMAIN\user")
+	parts := strings.Split(u.Username, "\\")
+	return parts[len(parts)-1]
+}
+
+// getScoreKey gets the high-score table of the board size and rules of the config
+func getScoreKey(c config) scores.Key {
+	return scores.Key{Width: c.width, Height: c.height, Bombs: c.bombs, NoGuess: c.noGuess, FirstClick: c.firstClick}
+}
+
+// isRanked returns whether or not the game counts for the high scores: a
+// random board that is played without any help
+func (g *game) isRanked() bool {
+	return !g.c.practice && g.undos == 0 && g.hints == 0 && !g.assisted && g.playback == nil &&
+		g.c.seed == 0 && g.c.code == nil && g.c.board == nil
+}
+
+// addScore adds a won game to the high scores when it is ranked
+func (g *game) addScore() {
+	g.rank = -1
+	if g.scores == nil || g.stats == nil || g.state != stateWon || !g.isRanked() {
+		return
+	}
+	// a name that was cleared in the menu would not be loaded again
+	name := strings.TrimSpace(g.c.name)
+	if name == "" {
+		name = getUserName()
+	}
+	entry := scores.Entry{
+		Name:          name,
+		Milliseconds:  g.stats.Milliseconds,
+		BBBVPerSecond: g.stats.BBBVPerSecond(),
+		Date:          time.Now().Format("2006-01-02"),
+	}
+	g.rank = g.scores.Add(getScoreKey(g.c), entry)
+	if g.rank < 0 {
+		return
+	}
+	if err := g.scores.Save(); err != nil {
+		log.Println(err)
+	}
+}
+
+// drawHighScores draws the high-score scene of a table and returns its height
+// and whether or not "Back" was pressed
+func drawHighScores(store *scores.Store, key scores.Key, scale int, w float32) (float32, bool) {
+	gui.SetStyleProperty(gui.GlobalTextFontsize, int64(scale*10))
+	m := float32(scale * 5)
+	row := float32(scale * 20)
+	cy := m
+	gui.Label(rl.NewRectangle(m, cy, w-2*m, row), "High scores "+key.String())
+	cy += row + m
+	entries := []scores.Entry{}
+	if store != nil {
+		entries = store.Get(key)
+	}
+	if len(entries) == 0 {
+		gui.Label(rl.NewRectangle(m, cy, w-2*m, row), "No high scores yet")
+		cy += row + m
+	}
+	for i, entry := range entries {
+		seconds := strconv.FormatFloat(float64(entry.Milliseconds)/1000, 'f', 3, 64) + " s"
+		gui.Label(rl.NewRectangle(m, cy, w/2-m, row), strconv.Itoa(i+1)+". "+entry.Name)
+		gui.Label(rl.NewRectangle(w/2, cy, w/2-m, row), seconds)
+		cy += row
+		bbbv := strconv.FormatFloat(entry.BBBVPerSecond, 'f', 2, 64) + " 3BV/s"
+		gui.Label(rl.NewRectangle(m, cy, w/2-m, row), "   "+entry.Date)
+		gui.Label(rl.NewRectangle(w/2, cy, w/2-m, row), bbbv)
+		cy += row + m
+	}
+	cy += m
+	back := gui.Button(rl.NewRectangle(m, cy, w-2*m, row), "Back")
+	cy += row + m
+	return cy, back
+}
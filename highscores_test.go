@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mevdschee/raylib-go-mines/scores"
+)
+
+func TestEmptyNameIsSavedAsUserName(t *testing.T) {
+	c := randomConfig(0)
+	c.name = ""
+	g := newTestGame(t, c)
+	fileName, err := scores.DefaultFileName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.scores = scores.New(fileName)
+	g.click(4, 4)
+	for i := 0; g.state == statePlaying; i++ {
+		x, y := i%g.c.width, i/g.c.width
+		if !g.tiles[y][x].bomb && !g.tiles[y][x].open {
+			g.click(x, y)
+		}
+	}
+	if g.state != stateWon || g.rank != 0 {
+		t.Fatalf("expected a won game with the first high score, got state %d and rank %d", g.state, g.rank)
+	}
+	loaded, err := scores.Load(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := loaded.Get(getScoreKey(g.c))
+	if len(entries) != 1 || entries[0].Name != getUserName() || entries[0].Name == "" {
+		t.Errorf("expected the score of %s to be loaded, got %v", getUserName(), entries)
+	}
+}
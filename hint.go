@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/mevdschee/raylib-go-mines/engine"
+)
+
+type hint struct {
+	deduction *engine.Deduction
+	text      string
+}
+
+// getView gets what the player sees of the board
+func (g *game) getView() *engine.View {
+	view := engine.NewView(g.c.width, g.c.height, g.c.bombs)
+	for y := 0; y < g.c.height; y++ {
+		for x := 0; x < g.c.width; x++ {
+			if g.tiles[y][x].open {
+				view.Set(x, y, g.tiles[y][x].number)
+			} else if g.tiles[y][x].marked {
+				view.Set(x, y, engine.Flagged)
+			}
+		}
+	}
+	return view
+}
+
+// showHint finds a provably safe cell (or else a provable bomb) and explains why
+func (g *game) showHint() {
+	if g.isOver() {
+		return
+	}
+	if g.board == nil {
+		g.hint = &hint{text: "Click anywhere to start, the first click is safe."}
+		return
+	}
+	view := g.getView()
+	var found *engine.Deduction
+	for _, d := range engine.Solve(view) {
+		d := d
+		if !d.Bomb {
+			found = &d
+			break
+		}
+		if found == nil && !g.tiles[d.Y][d.X].marked {
+			found = &d
+		}
+	}
+	if found == nil {
+		g.hint = &hint{text: "There is no certain move, you have to guess."}
+		return
+	}
+	// only a hint that shows a move counts
+	g.hints++
+	g.hint = &hint{deduction: found, text: engine.Explain(view, *found)}
+}
+
+func (g *game) drawHint(scale int) {
+	if g.hint == nil {
+		return
+	}
+	s := int32(scale)
+	if d := g.hint.deduction; d != nil {
+		color := rl.Green
+		if d.Bomb {
+			color = rl.Red
+		}
+		r := rl.NewRectangle(float32((12+d.X*16)*scale), float32((55+d.Y*16)*scale), float32(16*scale), float32(16*scale))
+		rl.DrawRectangleRec(r, rl.Fade(color, 0.3))
+		rl.DrawRectangleLinesEx(r, s, color)
+	}
+	width, _ := g.getSize()
+	fontSize := 5 * s
+	lines := wrapText(g.hint.text, int32(width*scale)-4*s, fontSize)
+	height := int32(len(lines))*(fontSize+s) + 2*s
+	top := int32(rl.GetScreenHeight()) - height
+	rl.DrawRectangle(0, top, int32(width*scale), height, rl.Fade(rl.Black, 0.7))
+	for i, line := range lines {
+		rl.DrawText(line, 2*s, top+s+int32(i)*(fontSize+s), fontSize, rl.White)
+	}
+}
+
+// wrapText splits the text in lines that fit the width
+func wrapText(text string, width, fontSize int32) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && rl.MeasureText(line+" "+word, fontSize) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mevdschee/raylib-go-mines/engine"
+)
+
+func TestShowHintBeforeStart(t *testing.T) {
+	g := newTestGame(t, randomConfig(1))
+	g.showHint()
+	if g.hint == nil || g.hint.deduction != nil || g.hints != 0 {
+		t.Errorf("expected a hint to start without counting it")
+	}
+}
+
+func TestShowHint(t *testing.T) {
+	// a 5x1 board with a bomb in the middle
+	g := newTestGame(t, boardConfig(newTestBoard(5, 1, 2, 0)))
+	g.click(0, 0)
+	view := g.getView()
+	g.showHint()
+	d := g.hint.deduction
+	if d == nil || g.hints != 1 {
+		t.Fatalf("expected a hint with a deduction to be counted")
+	}
+	if d.Bomb || d.X < 3 || g.hint.text != engine.Explain(view, *d) {
+		t.Errorf("expected a safe cell right of the bomb with its explanation, got %+v: %s", d, g.hint.text)
+	}
+	g.click(3, 0)
+	g.click(4, 0)
+	if g.state != stateWon || g.stats == nil || g.stats.Hints != 1 {
+		t.Fatalf("expected the game to be won with 1 hint, got state %d", g.state)
+	}
+	found := false
+	for _, line := range g.getStatsLines() {
+		found = found || line == "Hints: 1"
+	}
+	if !found {
+		t.Errorf("expected the hints in the stats lines, got %v", g.getStatsLines())
+	}
+	g.showHint()
+	if g.hints != 1 {
+		t.Errorf("expected no hint after the game is over")
+	}
+}
+
+func TestShowHintWithoutCertainMove(t *testing.T) {
+	// a 3x3 board with a bomb in the bottom right corner, the 1 in the middle
+	// does not tell which of its neighbours is the bomb
+	g := newTestGame(t, boardConfig(newTestBoard(3, 3, 2, 2)))
+	g.click(1, 1)
+	g.showHint()
+	if g.hint == nil || g.hint.deduction != nil || g.hints != 0 {
+		t.Errorf("expected no certain move and no hint counted, got %d hints", g.hints)
+	}
+}
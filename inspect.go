@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mevdschee/raylib-go-mines/inspector"
+)
+
+type boardJSON struct {
+	Width  int          `json:"width"`
+	Height int          `json:"height"`
+	Bombs  int          `json:"bombs"`
+	State  string       `json:"state"`
+	Tiles  [][]tileJSON `json:"tiles"`
+}
+
+type tileJSON struct {
+	Open     bool `json:"open,omitempty"`
+	Marked   bool `json:"marked,omitempty"`
+	Question bool `json:"question,omitempty"`
+	Bomb     bool `json:"bomb,omitempty"`
+	Number   int  `json:"number,omitempty"`
+}
+
+var stateNames = []string{"waiting", "playing", "won", "lost", "hit"}
+
+func (g *game) getBoard() boardJSON {
+	board := boardJSON{
+		Width:  g.c.width,
+		Height: g.c.height,
+		Bombs:  g.c.bombs,
+		State:  stateNames[g.state],
+		Tiles:  make([][]tileJSON, g.c.height),
+	}
+	for y := 0; y < g.c.height; y++ {
+		board.Tiles[y] = make([]tileJSON, g.c.width)
+		for x := 0; x < g.c.width; x++ {
+			t := g.tiles[y][x]
+			board.Tiles[y][x] = tileJSON{Open: t.open, Marked: t.marked, Question: t.question, Bomb: t.bomb, Number: t.number}
+		}
+	}
+	return board
+}
+
+// loadBoard replaces the tiles with the tiles of the board, the board must
+// have the size of the current game, numbers are recalculated
+func (g *game) loadBoard(board boardJSON) error {
+	if len(board.Tiles) != g.c.height {
+		return fmt.Errorf("loadBoard: expected %d rows, got %d", g.c.height, len(board.Tiles))
+	}
+	for y := 0; y < g.c.height; y++ {
+		if len(board.Tiles[y]) != g.c.width {
+			return fmt.Errorf("loadBoard: expected %d columns in row %d, got %d", g.c.width, y, len(board.Tiles[y]))
+		}
+	}
+	g.restart()
+	g.bombs = 0
+	for y := 0; y < g.c.height; y++ {
+		for x := 0; x < g.c.width; x++ {
+			t := board.Tiles[y][x]
+			g.tiles[y][x] = tile{open: t.Open, marked: t.Marked, question: t.Question, bomb: t.Bomb}
+			if t.Open {
+				g.closed--
+			}
+			if t.Bomb {
+				g.bombs++
+			}
+		}
+	}
+	g.c.bombs = g.bombs
+	for y := 0; y < g.c.height; y++ {
+		for x := 0; x < g.c.width; x++ {
+			if g.tiles[y][x].marked {
+				g.bombs--
+			}
+			if g.tiles[y][x].bomb {
+				g.forEachNeighbour(x, y, func(x, y int) {
+					g.tiles[y][x].number++
+				})
+			}
+		}
+	}
+	g.state = statePlaying
+	return nil
+}
+
+func (g *game) inspect(addr string) (*inspector.Inspector, error) {
+	i := inspector.New()
+	i.Get("/movie", func() (interface{}, error) {
+		if g.movie == nil {
+			return nil, fmt.Errorf("movie not loaded")
+		}
+		return g.movie.Tree(), nil
+	})
+	i.Get("/board", func() (interface{}, error) {
+		return g.getBoard(), nil
+	})
+	i.Get("/stats", func() (interface{}, error) {
+		return g.stats, nil
+	})
+	i.Post("/board", func(body []byte) (interface{}, error) {
+		board := boardJSON{}
+		err := json.Unmarshal(body, &board)
+		if err != nil {
+			return nil, err
+		}
+		err = g.loadBoard(board)
+		if err != nil {
+			return nil, err
+		}
+		return g.getBoard(), nil
+	})
+	i.Post("/frame", func(body []byte) (interface{}, error) {
+		if g.movie == nil {
+			return nil, fmt.Errorf("movie not loaded")
+		}
+		command := struct {
+			Scene, Layer, Clip string
+			Index, Frame       int
+		}{}
+		err := json.Unmarshal(body, &command)
+		if err != nil {
+			return nil, err
+		}
+		return true, g.movie.GotoFrame(command.Scene, command.Layer, command.Clip, command.Index, command.Frame)
+	})
+	i.Post("/emit", func(body []byte) (interface{}, error) {
+		if g.movie == nil {
+			return nil, fmt.Errorf("movie not loaded")
+		}
+		command := struct{ Event string }{}
+		err := json.Unmarshal(body, &command)
+		if err != nil {
+			return nil, err
+		}
+		g.movie.Emit(command.Event)
+		return true, nil
+	})
+	_, err := i.Listen(addr)
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
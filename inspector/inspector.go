@@ -0,0 +1,179 @@
+package inspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Inspector is a debug server on localhost that runs its handlers on the main loop
+type Inspector struct {
+	mux      *http.ServeMux
+	routes   map[string]map[string]http.HandlerFunc
+	server   *http.Server
+	requests chan request
+	timeout  time.Duration
+}
+
+type request struct {
+	handle func() (interface{}, error)
+	done   chan response
+}
+
+type response struct {
+	value interface{}
+	err   error
+}
+
+// New creates a new inspector
+func New() *Inspector {
+	i := &Inspector{
+		mux:      http.NewServeMux(),
+		routes:   map[string]map[string]http.HandlerFunc{},
+		requests: make(chan request),
+		timeout:  5 * time.Second,
+	}
+	i.server = &http.Server{Handler: i.mux}
+	return i
+}
+
+// route adds a handler for a method on a path, a path may have a handler for
+// each method
+func (i *Inspector) route(method, path string, handler http.HandlerFunc) {
+	methods, ok := i.routes[path]
+	if !ok {
+		methods = map[string]http.HandlerFunc{}
+		i.routes[path] = methods
+		i.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			handler, ok := methods[r.Method]
+			if !ok {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			if err := checkLoopback(r); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			handler(w, r)
+		})
+	}
+	methods[method] = handler
+}
+
+// Get adds a handler for GET requests on a path
+func (i *Inspector) Get(path string, handler func() (interface{}, error)) {
+	i.route(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		i.serve(w, r, handler)
+	})
+}
+
+// Post adds a handler for POST requests with a JSON body on a path, the
+// content type must be JSON so that web pages can not post simple forms
+func (i *Inspector) Post(path string, handler func(body []byte) (interface{}, error)) {
+	i.route(http.MethodPost, path, func(w http.ResponseWriter, r *http.Request) {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		i.serve(w, r, func() (interface{}, error) {
+			return handler(body)
+		})
+	})
+}
+
+// isLoopback returns whether or not the host (without port) is on localhost
+func isLoopback(host string) bool {
+	ip := net.ParseIP(host)
+	return host == "localhost" || (ip != nil && ip.IsLoopback())
+}
+
+// checkLoopback rejects requests for another host name (DNS rebinding) and
+// requests from web pages that are not on localhost (cross-site requests)
+func checkLoopback(r *http.Request) error {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+	if !isLoopback(host) {
+		return fmt.Errorf("host '%s' is not on localhost", r.Host)
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !isLoopback(u.Hostname()) {
+		return fmt.Errorf("origin '%s' is not on localhost", origin)
+	}
+	return nil
+}
+
+func (i *Inspector) serve(w http.ResponseWriter, r *http.Request, handle func() (interface{}, error)) {
+	req := request{handle: handle, done: make(chan response, 1)}
+	select {
+	case i.requests <- req:
+	case <-r.Context().Done():
+		return
+	case <-time.After(i.timeout):
+		http.Error(w, "main loop did not respond", http.StatusServiceUnavailable)
+		return
+	}
+	res := <-req.done
+	if res.err != nil {
+		http.Error(w, res.err.Error(), http.StatusBadRequest)
+		return
+	}
+	data, err := json.MarshalIndent(res.value, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// Listen starts serving on a loopback address (e.g. "localhost:7070") in the background
+func (i *Inspector) Listen(addr string) (net.Addr, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if !isLoopback(host) {
+		return nil, fmt.Errorf("Listen: address '%s' is not on localhost", addr)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	go i.server.Serve(listener)
+	return listener.Addr(), nil
+}
+
+// Poll runs the pending requests, it must be called from the main loop
+func (i *Inspector) Poll() {
+	for {
+		select {
+		case req := <-i.requests:
+			value, err := req.handle()
+			req.done <- response{value: value, err: err}
+		default:
+			return
+		}
+	}
+}
+
+// Close stops the server
+func (i *Inspector) Close() error {
+	return i.server.Close()
+}
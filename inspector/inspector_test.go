@@ -0,0 +1,123 @@
+package inspector
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestInspector listens on a free loopback port and polls like the main loop
+func newTestInspector(t *testing.T) (*Inspector, string) {
+	i := New()
+	var count int
+	i.Get("/count", func() (interface{}, error) {
+		return count, nil
+	})
+	i.Post("/count", func(body []byte) (interface{}, error) {
+		count++
+		return count, nil
+	})
+	addr, err := i.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond):
+				i.Poll()
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(done)
+		i.Close()
+	})
+	return i, "http://" + addr.String()
+}
+
+func TestListenRequiresLoopback(t *testing.T) {
+	for _, addr := range []string{"0.0.0.0:0", ":0", "192.168.1.2:0", "example.com:7070"} {
+		if _, err := New().Listen(addr); err == nil {
+			t.Errorf("Listen(%q): expected an error", addr)
+		}
+	}
+}
+
+func TestRequests(t *testing.T) {
+	_, url := newTestInspector(t)
+	tests := []struct {
+		name        string
+		method      string
+		host        string
+		origin      string
+		contentType string
+		status      int
+	}{
+		{"get", "GET", "", "", "", http.StatusOK},
+		{"get from localhost", "GET", "localhost:7070", "", "", http.StatusOK},
+		{"post", "POST", "", "", "application/json", http.StatusOK},
+		{"post with charset", "POST", "", "", "application/json; charset=utf-8", http.StatusOK},
+		{"post from localhost", "POST", "localhost:7070", "http://localhost:8080", "application/json", http.StatusOK},
+		{"post from ipv6 loopback", "POST", "[::1]:7070", "http://[::1]", "application/json", http.StatusOK},
+		{"post form", "POST", "", "", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"post text", "POST", "", "", "text/plain", http.StatusUnsupportedMediaType},
+		{"post without content type", "POST", "", "", "", http.StatusUnsupportedMediaType},
+		{"post from web page", "POST", "", "https://example.com", "application/json", http.StatusForbidden},
+		{"post from null origin", "POST", "", "null", "application/json", http.StatusForbidden},
+		{"post with rebound host", "POST", "evil.example.com:7070", "", "application/json", http.StatusForbidden},
+		{"get with rebound host", "GET", "evil.example.com:7070", "", "", http.StatusForbidden},
+		{"get from web page", "GET", "", "http://evil.example.com", "", http.StatusForbidden},
+		{"put", "PUT", "", "", "application/json", http.StatusMethodNotAllowed},
+	}
+	for _, test := range tests {
+		req, err := http.NewRequest(test.method, url+"/count", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.host != "" {
+			req.Host = test.host
+		}
+		if test.origin != "" {
+			req.Header.Set("Origin", test.origin)
+		}
+		if test.contentType != "" {
+			req.Header.Set("Content-Type", test.contentType)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		if res.StatusCode != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.name, test.status, res.StatusCode)
+		}
+	}
+}
+
+func TestRejectedPostDoesNotRun(t *testing.T) {
+	_, url := newTestInspector(t)
+	req, _ := http.NewRequest("POST", url+"/count", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Origin", "https://example.com")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	res, err = http.Get(url + "/count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	if string(body) != "0" {
+		t.Errorf("expected count 0, got %s", body)
+	}
+}
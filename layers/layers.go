@@ -2,6 +2,7 @@ package layers
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/expr-lang/expr"
 	"github.com/mevdschee/raylib-go-mines/clips"
@@ -16,8 +17,8 @@ type Layer struct {
 
 // LayerJSON is a set of layers in JSON
 type LayerJSON struct {
-	Name  string
-	Clips []clips.ClipJSON
+	Name  string           `json:"name" schema:"required"`
+	Clips []clips.ClipJSON `json:"clips"`
 }
 
 // GetName gets the name of the scene
@@ -25,6 +26,11 @@ func (l *Layer) GetName() string {
 	return l.name
 }
 
+// GetClips gets the clips of the layer in drawing order
+func (l *Layer) GetClips() []*clips.Clip {
+	return l.clips
+}
+
 // New creates a new layer
 func New(name string) *Layer {
 	return &Layer{
@@ -45,6 +51,50 @@ func eval(expression string, parameters map[string]interface{}) (int, error) {
 	return value.(int), nil
 }
 
+// Handler compiles an expression into an event handler, the parameters
+// (including the functions to emit events and call actions) are captured
+func Handler(expression string, parameters map[string]interface{}) (func(), error) {
+	if len(expression) == 0 {
+		return nil, nil
+	}
+	prog, err := expr.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	env := map[string]interface{}{}
+	for k, v := range parameters {
+		env[k] = v
+	}
+	return func() {
+		_, err := expr.Run(prog, env)
+		if err != nil {
+			log.Printf("Handler '%s': %v", expression, err)
+		}
+	}, nil
+}
+
+func setHandlers(clip *clips.Clip, clipJSON clips.ClipJSON, parameters map[string]interface{}) error {
+	handlers := []struct {
+		expression string
+		set        func(func())
+	}{
+		{clipJSON.OnPress, clip.OnPress},
+		{clipJSON.OnLongPress, clip.OnLongPress},
+		{clipJSON.OnRelease, clip.OnRelease},
+		{clipJSON.OnReleaseOutside, clip.OnReleaseOutside},
+	}
+	for _, h := range handlers {
+		fn, err := Handler(h.expression, parameters)
+		if err != nil {
+			return fmt.Errorf("Handler in '%s': %v", h.expression, err)
+		}
+		if fn != nil {
+			h.set(fn)
+		}
+	}
+	return nil
+}
+
 // FromJSON creates a new layer from JSON
 func FromJSON(spriteMap sprites.SpriteMap, layerJSON LayerJSON, parameters map[string]interface{}) (*Layer, error) {
 	layer := Layer{
@@ -81,11 +131,27 @@ func FromJSON(spriteMap sprites.SpriteMap, layerJSON LayerJSON, parameters map[s
 			if err != nil {
 				return nil, fmt.Errorf("Height in '%s': %v", clipJSON.Height, err)
 			}
-			if width == 0 {
-				layer.Add(clips.New(sprite, clipJSON.Name, x, y))
+			frame, err := eval(clipJSON.Frame, parameters)
+			if err != nil {
+				return nil, fmt.Errorf("Frame in '%s': %v", clipJSON.Frame, err)
+			}
+			var clip *clips.Clip
+			if clipJSON.Emitter != nil {
+				clip = clips.NewEmitter(sprite, clipJSON.Name, x, y, *clipJSON.Emitter)
+			} else if width == 0 {
+				clip = clips.New(sprite, clipJSON.Name, x, y)
 			} else {
-				layer.Add(clips.NewScaled(sprite, clipJSON.Name, x, y, width, height))
+				clip = clips.NewScaled(sprite, clipJSON.Name, x, y, width, height)
+			}
+			clip.GotoFrame(frame)
+			if clipJSON.Animation != "" && !clip.Play(clipJSON.Animation) {
+				return nil, fmt.Errorf("Could not find animation '%s' for clip with name '%s'", clipJSON.Animation, clipJSON.Name)
 			}
+			err = setHandlers(clip, clipJSON, parameters)
+			if err != nil {
+				return nil, err
+			}
+			layer.Add(clip)
 		}
 	}
 	return &layer, nil
@@ -103,10 +169,10 @@ func (l *Layer) Draw(scale int) {
 	}
 }
 
-// Update updates the layer
-func (l *Layer) Update(scale int) (err error) {
+// Update updates the layer with the pointer
+func (l *Layer) Update(p clips.Pointer) (err error) {
 	for _, clip := range l.clips {
-		err = clip.Update(scale)
+		err = clip.Update(p)
 		if err != nil {
 			break
 		}
@@ -114,6 +180,13 @@ func (l *Layer) Update(scale int) (err error) {
 	return err
 }
 
+// Tick advances the clips of the layer in time
+func (l *Layer) Tick() {
+	for _, clip := range l.clips {
+		clip.Tick()
+	}
+}
+
 // GetClip gets a clip from the layer
 func (l *Layer) GetClip(clip string, i int) (*clips.Clip, error) {
 	n := 0
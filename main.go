@@ -3,15 +3,24 @@ package main
 import (
 	"bytes"
 	_ "embed"
+	"flag"
 	"image/png"
 	"log"
-	"math/rand"
+	"strconv"
 	"time"
 
 	gui "github.com/gen2brain/raylib-go/raygui"
 	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/mevdschee/raylib-go-mines/audio"
 	"github.com/mevdschee/raylib-go-mines/clips"
+	"github.com/mevdschee/raylib-go-mines/clocks"
+	"github.com/mevdschee/raylib-go-mines/daily"
+	"github.com/mevdschee/raylib-go-mines/engine"
+	"github.com/mevdschee/raylib-go-mines/inspector"
 	"github.com/mevdschee/raylib-go-mines/movies"
+	"github.com/mevdschee/raylib-go-mines/replays"
+	"github.com/mevdschee/raylib-go-mines/scores"
+	"github.com/mevdschee/raylib-go-mines/settings"
 	"github.com/mevdschee/raylib-go-mines/sprites"
 )
 
@@ -21,52 +30,86 @@ var spriteMapImage []byte
 //go:embed minesicon.png
 var minesIconImage []byte
 
-const spriteMapMeta = `
-	[{"name":"display","x":28,"y":82,"width":41,"height":25,"count":1},
-	{"name":"icons","x":0,"y":0,"width":16,"height":16,"count":17,"grid":9},
-	{"name":"digits","x":0,"y":33,"width":11,"height":21,"count":11,"gap":1},
-	{"name":"buttons","x":0,"y":55,"width":26,"height":26,"count":5,"gap":1},
-	{"name":"controls","x":0,"y":82,"widths":[12,1,12],"heights":[11,1,11],"gap":1},
-	{"name":"field","x":0,"y":96,"widths":[12,1,12],"heights":[11,1,11],"gap":1}]`
-
-const movieScenes = `
-	[{"name":"game","layers":[{"name":"bg","clips":[
-		{"sprite":"controls","x":"0","y":"0","width":"w*16+24","height":"55"},
-		{"sprite":"field","x":"0","y":"44","width":"w*16+24","height":"h*16+22"},
-		{"sprite":"display","x":"16","y":"15"},
-		{"sprite":"display","x":"w*16-33","y":"15"}
-	]},{"name":"fg","clips":[
-		{"sprite":"digits","name":"bombs","repeat":"3","x":"18+i*13","y":"17"},
-		{"sprite":"digits","name":"time","repeat":"3","x":"w*16-31+i*13","y":"17"},
-		{"sprite":"buttons","name":"button","x":"(w*16)/2-1","y":"15"},
-		{"sprite":"icons","name":"icons","repeat":"w*h","x":"12+(i%w)*16","y":"55+floor(i/w)*16"}
-	]}]}]`
+//go:embed winxpskin.json
+var spriteMapMeta string
+
+//go:embed movie.json
+var movieScenes string
+
+//go:embed menu.json
+var menuButtons string
 
 type config struct {
-	scale   int
-	width   int
-	height  int
-	bombs   int
-	holding int
+	scale      int
+	width      int
+	height     int
+	bombs      int
+	holding    int
+	volume     float32
+	muted      bool
+	seed       uint64
+	code       *engine.Code
+	board      *engine.Board
+	noGuess    bool
+	firstClick engine.FirstClick
+	questions  bool
+	practice   bool
+	name       string
+	skin       string
+	presets    []settings.Preset
+	daily      string
 }
 
 type game struct {
-	c      config
-	movie  *movies.Movie
-	button int
-	bombs  int
-	closed int
-	state  int
-	time   int64
-	tiles  [][]tile
+	c            config
+	movie        *movies.Movie
+	image        []byte
+	spriteList   []*sprites.Sprite
+	pending      func()
+	audio        *audio.Player
+	clock        clocks.Clock
+	seed         uint64
+	code         *engine.Code
+	board        *engine.Board
+	button       int
+	bombs        int
+	closed       int
+	state        int
+	time         int64
+	hitAt        int64
+	second       int
+	hint         *hint
+	hints        int
+	heatmap      heatmap
+	recording    *move
+	history      []*move
+	future       []*move
+	undos        int
+	clicks       engine.Clicks
+	stats        *engine.Stats
+	showStats    bool
+	scores       *scores.Store
+	rank         int
+	assisted     bool
+	dailyHistory *daily.History
+	dailyRanked  bool
+	replay       *replays.Replay
+	playback     *playback
+	ticks        int
+	savedAt      int
+	down         bool
+	pointerX     int
+	pointerY     int
+	tiles        [][]tile
 }
 
 type tile struct {
-	open    bool
-	marked  bool
-	bomb    bool
-	pressed bool
-	number  int
+	open     bool
+	marked   bool
+	question bool
+	bomb     bool
+	pressed  bool
+	number   int
 }
 
 const (
@@ -74,6 +117,9 @@ const (
 	statePlaying
 	stateWon
 	stateLost
+	// stateHit is a mine hit in practice mode, the game is paused until the
+	// move is undone
+	stateHit
 )
 
 const (
@@ -84,6 +130,9 @@ const (
 	buttonPressed
 )
 
+// stateButtons are the frames of the button in each state
+var stateButtons = []int{buttonPlaying, buttonPlaying, buttonWon, buttonLost}
+
 const (
 	iconEmpty = iota
 	iconNumberOne
@@ -104,6 +153,13 @@ const (
 	iconQuestionPressed
 )
 
+// the game is updated at a fixed timestep, independent of the frame rate
+const (
+	ticksPerSecond = 30
+	tick           = time.Second / ticksPerSecond
+	maxFrameTime   = 250 * time.Millisecond
+)
+
 var clipCache map[string][]*clips.Clip
 
 var version string
@@ -117,7 +173,31 @@ func (g *game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeigh
 }
 
 func (g *game) init() {
-	spriteMap, err := sprites.NewSpriteMap(spriteMapImage, spriteMapMeta)
+	image, err := loadSkin(g.c.skin)
+	if err != nil {
+		log.Println(err)
+		image = spriteMapImage
+	}
+	spriteList, err := loadSkinSprites(g.c.skin)
+	if err != nil {
+		log.Println(err)
+		spriteList = nil
+	}
+	g.setMovie(image, spriteList)
+}
+
+// setMovie creates the movie on the sprite map image, without an image the
+// movie has no textures and can only be updated (e.g. in the tests), without
+// sprites it has the sprites of the built-in sprite map
+func (g *game) setMovie(image []byte, spriteList []*sprites.Sprite) {
+	g.image, g.spriteList = image, spriteList
+	var spriteMap sprites.SpriteMap
+	var err error
+	if spriteList == nil {
+		spriteMap, err = sprites.NewSpriteMap(image, spriteMapMeta)
+	} else {
+		spriteMap, err = sprites.FromSprites(image, spriteList)
+	}
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -130,48 +210,84 @@ func (g *game) init() {
 		log.Fatalln(err)
 	}
 	g.movie = movie
+	g.movie.SetClock(g.clock)
 	clipCache = map[string][]*clips.Clip{}
 }
 
 func (g *game) getClips(clip string) []*clips.Clip {
+	return g.getLayerClips("fg", clip)
+}
+
+func (g *game) getLayerClips(layer, clip string) []*clips.Clip {
 	if clipCache == nil {
 		clipCache = map[string][]*clips.Clip{}
 	}
-	cache, ok := clipCache[clip]
+	cache, ok := clipCache[layer+"/"+clip]
 	if ok {
 		return cache
 	}
-	clips, err := g.movie.GetClips("game", "fg", clip)
+	clips, err := g.movie.GetClips("game", layer, clip)
 	if err != nil {
 		log.Fatal(err)
 	}
-	clipCache[clip] = clips
+	clipCache[layer+"/"+clip] = clips
 	return clips
 }
 
 func (g *game) setHandlers() {
-	button := g.getClips("button")[0]
-	button.OnPress(func() {
+	g.movie.Subscribe("buttonPress", func() {
 		g.button = buttonPressed
 	})
-	button.OnRelease(func() {
+	g.movie.Subscribe("restart", func() {
 		if g.button == buttonPressed {
+			if g.playback != nil {
+				// a replay ends where the player restarted
+				g.button = stateButtons[g.state]
+				return
+			}
 			g.restart()
 		}
 	})
-	button.OnReleaseOutside(func() {
-		if g.button == buttonPressed {
+	g.movie.RegisterAction("preset", func(args ...interface{}) {
+		if g.playback != nil {
+			return
+		}
+		p, err := getPreset(args)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		// the movie is replaced after it is updated
+		g.pending = func() {
+			// the replay is saved before the board size changes
+			g.saveReplay()
+			g.c.width, g.c.height, g.c.bombs = p.Width, p.Height, p.Bombs
+			g.c.seed, g.c.code, g.c.board, g.c.daily = 0, nil, nil, ""
+			g.setMovie(g.image, g.spriteList)
+			g.setHandlers()
 			g.restart()
 		}
 	})
+	g.movie.RegisterAction("skin", func(args ...interface{}) {
+		if g.playback != nil {
+			return
+		}
+		g.pending = func() {
+			g.c.skin = getNextSkin(g.c.skin)
+			g.init()
+			g.setHandlers()
+		}
+	})
 	icons := g.getClips("icons")
 	for y := 0; y < g.c.height; y++ {
 		for x := 0; x < g.c.width; x++ {
 			px, py := x, y
+			icons[y*g.c.width+x].SetLongPress(time.Duration(g.c.holding) * tick)
 			icons[y*g.c.width+x].OnPress(func() {
-				if g.state == stateWon || g.state == stateLost {
+				if g.isOver() {
 					return
 				}
+				g.hint = nil
 				if g.tiles[py][px].marked {
 					return
 				}
@@ -186,28 +302,49 @@ func (g *game) setHandlers() {
 				}
 			})
 			icons[y*g.c.width+x].OnLongPress(func() {
-				if g.state == stateWon || g.state == stateLost {
+				if g.isOver() {
 					return
 				}
-				g.onPressTile(px, py, true)
+				closed, open := g.closed, g.tiles[py][px].open
+				kind := moveFlag
+				if open {
+					kind = moveChord
+					g.clicks.Chord++
+				} else {
+					g.clicks.Right++
+				}
+				g.play(kind, px, py, func() {
+					g.onPressTile(px, py, true)
+				})
+				if open {
+					g.playMove(closed, true)
+				} else {
+					g.audio.Play(audio.CueFlag)
+				}
 				g.tiles[py][px].pressed = false
 			})
 			icons[y*g.c.width+x].OnRelease(func() {
-				if g.state == stateWon || g.state == stateLost {
+				if g.isOver() {
 					return
 				}
 				g.button = buttonPlaying
 				if g.tiles[py][px].open {
-					g.onPressTile(px, py, true)
+					closed := g.closed
+					g.clicks.Chord++
+					g.play(moveChord, px, py, func() {
+						g.onPressTile(px, py, true)
+					})
+					g.playMove(closed, true)
 				} else {
+					g.clicks.Left++
 					if g.tiles[py][px].pressed {
-						g.onPressTile(px, py, false)
+						g.reveal(px, py)
 					}
 				}
 				g.tiles[py][px].pressed = false
 			})
 			icons[y*g.c.width+x].OnReleaseOutside(func() {
-				if g.state == stateWon || g.state == stateLost {
+				if g.isOver() {
 					return
 				}
 				g.button = buttonPlaying
@@ -217,6 +354,25 @@ func (g *game) setHandlers() {
 	}
 }
 
+// reveal opens a closed tile as a move
+func (g *game) reveal(x, y int) {
+	closed := g.closed
+	g.play(moveReveal, x, y, func() {
+		g.onPressTile(x, y, false)
+	})
+	g.playMove(closed, false)
+}
+
+// clickTile makes a left click on a tile for the player, the click is
+// recorded in the replay as a press and release on the center of the tile
+func (g *game) clickTile(x, y int) {
+	g.pointerX, g.pointerY = 12+x*16+8, 55+y*16+8
+	g.record(replays.ActionPress)
+	g.record(replays.ActionRelease)
+	g.clicks.Left++
+	g.reveal(x, y)
+}
+
 func (g *game) forEachNeighbour(x, y int, do func(x, y int)) {
 	for i := 0; i < 9; i++ {
 		dy, dx := i/3-1, i%3-1
@@ -236,8 +392,11 @@ func (g *game) forEachNeighbour(x, y int, do func(x, y int)) {
 func (g *game) onPressTile(x, y int, long bool) {
 	if g.state == stateWaiting {
 		g.state = statePlaying
-		g.time = time.Now().UnixNano()
-		g.placeBombs(x, y, g.bombs)
+		g.time = g.clock.Now().UnixNano()
+		g.startDaily()
+		if g.board == nil {
+			g.placeBombs(x, y)
+		}
 	}
 	if !long && g.tiles[y][x].marked {
 		return
@@ -259,20 +418,32 @@ func (g *game) onPressTile(x, y int, long bool) {
 			}
 		}
 	} else {
+		g.touch(x, y)
 		if long {
 			if g.tiles[y][x].marked {
 				g.tiles[y][x].marked = false
+				g.tiles[y][x].question = g.c.questions
 				g.bombs++
+			} else if g.tiles[y][x].question {
+				g.tiles[y][x].question = false
 			} else {
 				g.tiles[y][x].marked = true
 				g.bombs--
 			}
 		} else {
 			g.tiles[y][x].open = true
+			g.tiles[y][x].question = false
 			g.closed--
 			if g.tiles[y][x].bomb {
 				g.state = stateLost
 				g.button = buttonLost
+				g.explode(x, y)
+				if g.c.practice {
+					// the game does not end, the clock stops until the move is undone
+					g.state = stateHit
+					g.hitAt = g.clock.Now().UnixNano()
+					g.hint = &hint{text: "Practice: press Z to undo the last move."}
+				}
 				return
 			}
 			if g.tiles[y][x].number == 0 {
@@ -284,6 +455,43 @@ func (g *game) onPressTile(x, y int, long bool) {
 	}
 }
 
+// playMove plays the sound of a move that opened tiles, followed by the win
+func (g *game) playMove(closed int, chord bool) {
+	opened := closed - g.closed
+	switch {
+	case g.state == stateLost || g.state == stateHit:
+		g.audio.Play(audio.CueExplode)
+		return
+	case opened == 0:
+		return
+	case chord:
+		g.audio.Play(audio.CueChord)
+	case opened > 1:
+		g.audio.Play(audio.CueFloodFill)
+	default:
+		g.audio.Play(audio.CueReveal)
+	}
+	if g.state == stateWon {
+		g.audio.Play(audio.CueWin)
+	}
+}
+
+// isOver returns whether or not the game takes no more moves, a mine hit in
+// practice mode takes no moves until it is undone
+func (g *game) isOver() bool {
+	return g.state == stateWon || g.state == stateLost || g.state == stateHit
+}
+
+func (g *game) explode(x, y int) {
+	explosion := g.getLayerClips("fx", "explosion")[0]
+	explosion.Burst(12+x*16, 55+y*16, 40)
+}
+
+func (g *game) celebrate() {
+	confetti := g.getLayerClips("fx", "confetti")[0]
+	confetti.Burst((g.c.width*16)/2-1, 15, 80)
+}
+
 func (g *game) setButton() {
 	button := g.getClips("button")[0]
 	button.GotoFrame(g.button)
@@ -312,10 +520,14 @@ func (g *game) setNumbers() {
 		bombs /= 10
 	}
 	if g.state == statePlaying || g.state == stateWaiting {
-		time := int((time.Now().UnixNano() - g.time) / 1000000000)
+		time := int((g.clock.Now().UnixNano() - g.time) / 1000000000)
 		if time > 999 {
 			time = 999
 		}
+		if g.state == statePlaying && time != g.second {
+			g.audio.Play(audio.CueTick)
+		}
+		g.second = time
 		timeDigits := g.getClips("time")
 		for i := 0; i < 3; i++ {
 			timeDigits[2-i].GotoFrame(time % 10)
@@ -350,6 +562,8 @@ func (g *game) setTiles() {
 							} else {
 								icon = iconBomb
 							}
+						} else if g.tiles[y][x].question {
+							icon = iconQuestionMark
 						}
 					}
 				}
@@ -362,9 +576,17 @@ func (g *game) setTiles() {
 				icon := iconClosed
 				if g.tiles[y][x].open {
 					icon = g.tiles[y][x].number
+					if g.tiles[y][x].bomb {
+						icon = iconAnswerIsBomb
+					}
 				} else {
 					if g.tiles[y][x].marked {
 						icon = iconMarked
+					} else if g.tiles[y][x].question {
+						icon = iconQuestionMark
+						if g.tiles[y][x].pressed {
+							icon = iconQuestionPressed
+						}
 					} else {
 						if g.tiles[y][x].pressed {
 							icon = iconEmpty
@@ -380,42 +602,97 @@ func (g *game) setTiles() {
 	}
 }
 
+// Update updates the game with the mouse, or with the replay during playback
 func (g *game) Update(scale int) error {
+	if g.playback != nil {
+		g.applyEvents()
+		return g.update(g.playback.pointer)
+	}
+	p := clips.ReadMouse(scale)
+	g.recordPointer(p)
+	return g.update(p)
+}
+
+func (g *game) update(p clips.Pointer) error {
 	if g.movie == nil {
 		g.init()
 		g.setHandlers()
 	}
 	if g.state == stateWaiting {
-		g.time = time.Now().UnixNano()
+		g.time = g.clock.Now().UnixNano()
 	}
 	g.setButton()
 	g.setNumbers()
 	g.setTiles()
-	if g.state == statePlaying {
-		if g.closed == g.c.bombs {
-			g.state = stateWon
-			g.button = buttonWon
-		}
-	}
 	//touch.UpdateTouchIDs()
-	return g.movie.Update(scale)
+	err := g.movie.UpdatePointer(p)
+	if g.pending != nil {
+		pending := g.pending
+		g.pending = nil
+		pending()
+	}
+	return err
+}
+
+// checkWon ends the game when only the bombs are closed
+func (g *game) checkWon() {
+	if g.state == statePlaying && g.closed == g.c.bombs {
+		g.state = stateWon
+		g.button = buttonWon
+		g.celebrate()
+	}
+}
+
+// Tick advances the game to the time of the clock
+func (g *game) Tick() {
+	g.ticks++
+	if g.movie != nil {
+		g.movie.Tick()
+	}
 }
 
 func (g *game) Draw(scale int) {
 	g.movie.Draw(scale)
+	g.drawHeatmap(scale)
+	g.drawHint(scale)
+	g.drawStats(scale)
+	g.drawPlayback(scale)
 }
 
-func newGame(c config) *game {
-	g := &game{c: c}
+func newGame(c config, player *audio.Player, clock clocks.Clock) *game {
+	g := &game{c: c, audio: player, clock: clock}
 	return g
 }
 
 func (g *game) restart() {
+	g.saveReplay()
+	if g.movie != nil {
+		g.getLayerClips("fx", "explosion")[0].ResetEmitter()
+		g.getLayerClips("fx", "confetti")[0].ResetEmitter()
+	}
 	g.button = buttonPlaying
 	g.bombs = g.c.bombs
 	g.closed = g.c.width * g.c.height
 	g.state = stateWaiting
-	g.time = time.Now().UnixNano()
+	g.time = g.clock.Now().UnixNano()
+	g.second = 0
+	g.hint = nil
+	g.hints = 0
+	g.heatmap.probabilities = nil
+	g.history = []*move{}
+	g.future = []*move{}
+	g.undos = 0
+	g.clicks = engine.Clicks{}
+	g.stats = nil
+	g.rank = -1
+	g.assisted = false
+	g.dailyRanked = false
+	g.code = nil
+	g.board = nil
+	g.seed = g.c.seed
+	if g.seed == 0 {
+		g.seed = uint64(g.clock.Now().UnixNano())
+	}
 	g.tiles = make([][]tile, g.c.height)
 	for y := 0; y < g.c.height; y++ {
 		g.tiles[y] = make([]tile, g.c.width)
@@ -423,82 +700,331 @@ func (g *game) restart() {
 			g.tiles[y][x] = tile{}
 		}
 	}
+	if g.c.board != nil {
+		// a board file starts from its exact layout and state
+		g.setBoard(g.c.board)
+		for y := 0; y < g.c.height; y++ {
+			for x := 0; x < g.c.width; x++ {
+				// the game can not start from a mine hit, an open mine stays covered
+				if g.c.board.IsOpen(x, y) && !g.c.board.IsBomb(x, y) {
+					g.tiles[y][x].open = true
+					g.closed--
+				}
+				if g.c.board.IsFlagged(x, y) {
+					g.tiles[y][x].marked = true
+					g.bombs--
+				}
+				if g.c.board.IsQuestion(x, y) {
+					g.tiles[y][x].question = true
+				}
+			}
+		}
+	}
+	if g.c.code != nil {
+		g.seed = g.c.code.Seed
+		g.placeBombs(g.c.code.X, g.c.code.Y)
+	}
+	g.newReplay()
+	if g.c.code != nil && (g.playback == nil || g.playback.replay.Version < 2) {
+		// a shared game starts with the first click of the code, a replay
+		// (since version 2) has that click in its events
+		g.clickTile(g.c.code.X, g.c.code.Y)
+	}
 }
 
-func (g *game) placeBombs(x, y, bombs int) {
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	b := bombs
-	g.tiles[y][x].bomb = true
-	for b > 0 {
-		x, y := rng.Intn(g.c.width), rng.Intn(g.c.height)
-		if !g.tiles[y][x].bomb {
-			g.tiles[y][x].bomb = true
-			b--
-			g.forEachNeighbour(x, y, func(x, y int) {
-				g.tiles[y][x].number++
-			})
+// placeBombs generates the board from the seed, keeping the first click (x,y) free
+func (g *game) placeBombs(x, y int) {
+	code := engine.Code{
+		Width:      g.c.width,
+		Height:     g.c.height,
+		Bombs:      g.c.bombs,
+		Seed:       g.seed,
+		X:          x,
+		Y:          y,
+		NoGuess:    g.c.noGuess,
+		FirstClick: g.c.firstClick,
+	}
+	g.setBoard(code.Generate())
+	g.code = &code
+}
+
+// setBoard copies the bombs and numbers of the board into the tiles
+func (g *game) setBoard(board *engine.Board) {
+	for y := 0; y < g.c.height; y++ {
+		for x := 0; x < g.c.width; x++ {
+			g.tiles[y][x].bomb = board.IsBomb(x, y)
+			g.tiles[y][x].number = board.Number(x, y)
 		}
 	}
-	g.tiles[y][x].bomb = false
+	g.board = board
+}
+
+// getTitle gets the window title with the seed, or the shareable code once the board is generated
+func (g *game) getTitle() string {
+	title := "Raylib Go Mines v" + version
+	if g.playback != nil {
+		title += " - replay"
+	}
+	if g.c.daily != "" {
+		title += " - daily " + g.c.daily
+	}
+	if g.c.practice {
+		title += " - practice"
+	}
+	if g.hints > 0 {
+		title += " - hints " + strconv.Itoa(g.hints)
+	}
+	if g.code != nil {
+		return title + " - code " + g.code.String()
+	}
+	if g.c.board != nil {
+		return title + " - custom board"
+	}
+	return title + " - seed " + strconv.FormatUint(g.seed, 10)
+}
+
+func digitsOnly(text string) string {
+	digits := []byte{}
+	for i := 0; i < len(text); i++ {
+		if text[i] >= '0' && text[i] <= '9' {
+			digits = append(digits, text[i])
+		}
+	}
+	return string(digits)
 }
 
 func main() {
+	inspect := flag.String("inspect", "", "serve the scene tree and game state on a localhost address (e.g. localhost:7070)")
+	seed := flag.Uint64("seed", 0, "seed of the board generation (0 is random)")
+	code := flag.String("code", "", "shareable code of a board to play")
+	replay := flag.String("replay", "", "replay file to play back (JSON or RAW Vienna)")
+	boardFile := flag.String("board", "", "text board file to play")
+	flag.Parse()
 	//rl.SetTraceLog(rl.LogError)
 	title := "Raylib Go Mines v" + version
-	c := config{
-		scale:   2,
-		width:   9,
-		height:  9,
-		bombs:   10,
-		holding: 15,
-	}
-	g := newGame(c)
+	settingsFile, err := settings.DefaultFileName()
+	if err != nil {
+		log.Println(err)
+	}
+	prefs, err := settings.Load(settingsFile)
+	if err != nil {
+		log.Println(err)
+	}
+	c := newConfig(prefs)
+	c.seed = *seed
 	menu := true
+	if *code != "" {
+		shared, err := engine.ParseCode(*code)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		c.width, c.height, c.bombs = shared.Width, shared.Height, shared.Bombs
+		c.noGuess, c.firstClick = shared.NoGuess, shared.FirstClick
+		c.code = &shared
+		menu = false
+	}
+	if *boardFile != "" {
+		board, err := loadBoardFile(*boardFile)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		c.setBoard(board)
+		menu = false
+	}
+	clock := clocks.NewManual(time.Now())
+	g := newGame(c, audio.New(audio.Null{}), clock)
+	if fileName, err := scores.DefaultFileName(); err == nil {
+		g.scores, err = scores.Load(fileName)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+	if fileName, err := daily.DefaultFileName(); err == nil {
+		g.dailyHistory, err = daily.Load(fileName)
+		if err != nil {
+			log.Println(err)
+		}
+	}
 	g.restart()
+	if *replay != "" {
+		r, err := loadReplay(*replay)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		err = g.startPlayback(r, clock)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		menu = false
+	}
 	width, height := g.getSize()
 	rl.InitWindow(int32(c.scale*width), int32(c.scale*height), title)
+	if bank, err := audio.BankFromDir("sounds"); err == nil {
+		player := audio.New(audio.NewRaylib())
+		if err := player.Load(bank); err != nil {
+			log.Println(err)
+		}
+		g.audio = player
+	}
+	g.audio.SetVolume(c.volume)
+	g.audio.SetMuted(c.muted)
 	rl.SetTargetFPS(30)
 	icon, err := png.Decode(bytes.NewReader(minesIconImage))
 	if err == nil {
 		rl.SetWindowIcon(*rl.NewImageFromImage(icon))
 	}
+	var debugServer *inspector.Inspector
+	if *inspect != "" {
+		debugServer, err = g.inspect(*inspect)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer debugServer.Close()
+	}
 
+	seedText := ""
+	if c.seed != 0 {
+		seedText = strconv.FormatUint(c.seed, 10)
+	}
+	codeText := ""
+	boardText := ""
+	menuError := ""
+	windowTitle := title
+	saved, err := loadSavedGame()
+	if err != nil {
+		log.Println(err)
+	}
+	menuActions := movies.New()
+	menuActions.RegisterAction("preset", func(args ...interface{}) {
+		p, err := getPreset(args)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		c.width, c.height, c.bombs = p.Width, p.Height, p.Bombs
+	})
+	menuActions.RegisterAction("skin", func(args ...interface{}) {
+		c.skin = getNextSkin(c.skin)
+	})
+	buttons, err := menuActions.ButtonsFromJSON(menuButtons)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	highScores := false
+	stepper := clocks.NewStepper(clock, tick, maxFrameTime)
 	for !rl.WindowShouldClose() {
+		frameTime := time.Duration(float64(rl.GetFrameTime()) * float64(time.Second))
+		if g.playback != nil {
+			g.controlPlayback()
+			g.updatePlayback(frameTime)
+		} else {
+			stepper.Advance(frameTime, func() {
+				if !menu {
+					g.Tick()
+				}
+			})
+		}
+		if debugServer != nil {
+			debugServer.Poll()
+		}
+		if !menu {
+			g.autoSave()
+		}
+		if rl.IsKeyPressed(rl.KeyM) {
+			g.c.muted = !g.c.muted
+			c.muted = g.c.muted
+			g.audio.SetMuted(g.c.muted)
+		}
+		if rl.IsKeyPressed(rl.KeyH) && !menu && g.playback == nil {
+			g.record(replays.ActionHint)
+			g.showHint()
+		}
+		if rl.IsKeyPressed(rl.KeyZ) && !menu && g.c.practice && g.playback == nil {
+			g.record(replays.ActionUndo)
+			if g.undo() {
+				g.hint = nil
+			}
+		}
+		if rl.IsKeyPressed(rl.KeyY) && !menu && g.c.practice && g.playback == nil {
+			g.record(replays.ActionRedo)
+			g.redo()
+		}
+		if rl.IsKeyPressed(rl.KeyS) && !menu {
+			g.showStats = !g.showStats
+		}
+		if rl.IsKeyPressed(rl.KeyT) && !menu && !highScores {
+			highScores = true
+		}
+		if rl.IsKeyPressed(rl.KeyP) && !menu {
+			g.toggleHeatmap()
+		}
+		if rl.IsKeyPressed(rl.KeyF1) && g.movie != nil {
+			g.movie.SetDebug(!g.movie.IsDebug())
+		}
+		if !menu && g.getTitle() != windowTitle {
+			windowTitle = g.getTitle()
+			rl.SetWindowTitle(windowTitle)
+		}
 		rl.BeginDrawing()
 		rl.ClearBackground(rl.White)
-		if menu {
+		if highScores {
+			key := getScoreKey(g.c)
+			if menu {
+				key = getScoreKey(c)
+			}
+			w := float32(rl.GetScreenWidth())
+			cy, back := drawHighScores(g.scores, key, g.c.scale, w)
+			if back {
+				highScores = false
+				if !menu {
+					width, height := g.getSize()
+					rl.SetWindowSize(g.c.scale*width, g.c.scale*height)
+				}
+			} else if int(cy) != rl.GetScreenHeight() {
+				rl.SetWindowSize(int(w), int(cy))
+			}
+		} else if menu {
 			gui.SetStyleProperty(gui.GlobalTextFontsize, int64(g.c.scale*10))
 			w := float32(g.c.scale * width)
-			h := float32(g.c.scale * height)
 			m := float32(g.c.scale * 5)
 			cy := m
 			row := float32(g.c.scale * 20)
 			gui.Label(rl.NewRectangle(m, cy, w-2*m, row), title)
 			cy += row + m
-			beginner := gui.Button(rl.NewRectangle(m, cy, w-2*m, row), "Beginner")
-			if beginner {
-				c.height = 9
-				c.width = 9
-				c.bombs = 10
+			if saved != nil {
+				if gui.Button(rl.NewRectangle(m, cy, w-2*m, row), "Resume") {
+					if err := g.resume(saved); err != nil {
+						log.Println(err)
+					} else {
+						c = g.c
+						width, height := g.getSize()
+						rl.SetWindowSize(g.c.scale*width, g.c.scale*height)
+						rl.SetWindowPosition((rl.GetMonitorWidth(0)-g.c.scale*width)/2, (rl.GetMonitorHeight(0)-g.c.scale*height)/2)
+						menu = false
+					}
+					saved = nil
+				}
+				cy += row + m
 			}
-			cy += row + m/2
-			intermediate := gui.Button(rl.NewRectangle(m, cy, w-2*m, row), "Intermediate")
-			if intermediate {
-				c.height = 16
-				c.width = 16
-				c.bombs = 40
+			for _, button := range buttons {
+				if gui.Button(rl.NewRectangle(m, cy, w-2*m, row), button.Label) {
+					button.Press()
+				}
+				cy += row + m/2
 			}
-			cy += row + m/2
-			expert := gui.Button(rl.NewRectangle(m, cy, w-2*m, row), "Expert")
-			if expert {
-				c.height = 16
-				c.width = 30
-				c.bombs = 99
+			if len(c.presets) > 0 {
+				pw := (w - 2*m) / float32(settings.MaxPresets)
+				for i, p := range c.presets {
+					if gui.Button(rl.NewRectangle(m+float32(i)*pw, cy, pw-m/2, row), getPresetName(p)) {
+						c.width, c.height, c.bombs = p.Width, p.Height, p.Bombs
+					}
+				}
+				cy += row + m/2
 			}
-			cy += row + m + m
-			//gui.Label(rl.NewRectangle(m, cy, 0, row), "Scale:")
-			//c.scale = gui.Spinner(rl.NewRectangle(w/2-m, cy, w/2-m, row), c.scale, 1, 6)
-			//cy += row + m
+			cy += m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "Scale:")
+			c.scale = gui.Spinner(rl.NewRectangle(w/2-m, cy, w/2-m, row), c.scale, 1, 6)
+			cy += row + m
 			gui.Label(rl.NewRectangle(m, cy, 0, row), "Height:")
 			c.height = gui.Spinner(rl.NewRectangle(w/2-m, cy, w/2-m, row), c.height, 9, 50)
 			cy += row + m
@@ -506,22 +1032,139 @@ func main() {
 			c.width = gui.Spinner(rl.NewRectangle(w/2-m, cy, w/2-m, row), c.width, 9, 100)
 			cy += row + m
 			gui.Label(rl.NewRectangle(m, cy, 0, row), "Bombs:")
-			c.bombs = gui.Spinner(rl.NewRectangle(w/2-m, cy, w/2-m, row), c.bombs, 1, 999)
-			start := gui.Button(rl.NewRectangle(m, h-m-row, w-2*m, row), "Start")
+			// at least one cell is free, or the settings would reset the board size
+			maxBombs := c.width*c.height - 1
+			c.bombs = gui.Spinner(rl.NewRectangle(w/2-m, cy, w/2-m, row), c.bombs, 1, maxBombs)
+			if c.bombs > maxBombs {
+				c.bombs = maxBombs
+			}
+			cy += row + m
+			if gui.Button(rl.NewRectangle(w/2-m, cy, w/2-m, row), "Save preset") {
+				s := c.getSettings()
+				s.AddPreset(settings.Preset{Width: c.width, Height: c.height, Bombs: c.bombs})
+				c.presets = s.Presets
+			}
+			cy += row + m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "Skin:")
+			gui.Label(rl.NewRectangle(w/2-m, cy, w/2-m, row), getSkinName(c.skin))
+			cy += row + m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "Long press:")
+			c.holding = gui.Spinner(rl.NewRectangle(w/2-m, cy, w/2-m, row), c.holding, 0, 60)
+			cy += row + m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "Volume:")
+			c.volume = float32(gui.Spinner(rl.NewRectangle(w/2-m, cy, w/2-m, row), int(c.volume*100+0.5), 0, 100)) / 100
+			cy += row + m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "First click:")
+			if gui.Button(rl.NewRectangle(w/2-m, cy, w/2-m, row), c.firstClick.String()) {
+				c.firstClick = engine.FirstClick((int(c.firstClick) + 1) % len(engine.FirstClicks))
+			}
+			cy += row + m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "Questions:")
+			c.questions = gui.CheckBox(rl.NewRectangle(w/2-m, cy, row, row), c.questions)
+			cy += row + m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "Practice:")
+			c.practice = gui.CheckBox(rl.NewRectangle(w/2-m, cy, row, row), c.practice)
+			cy += row + m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "No guessing:")
+			c.noGuess = gui.CheckBox(rl.NewRectangle(w/2-m, cy, row, row), c.noGuess)
+			cy += row + m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "Name:")
+			c.name = gui.TextBox(rl.NewRectangle(w/2-m, cy, w/2-m, row), c.name)
+			cy += row + m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "Seed:")
+			seedText = digitsOnly(gui.TextBox(rl.NewRectangle(w/2-m, cy, w/2-m, row), seedText))
+			cy += row + m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "Code:")
+			codeText = gui.TextBox(rl.NewRectangle(w/2-m, cy, w/2-m, row), codeText)
+			cy += row + m
+			gui.Label(rl.NewRectangle(m, cy, 0, row), "Board file:")
+			boardText = gui.TextBox(rl.NewRectangle(w/2-m, cy, w/2-m, row), boardText)
+			cy += row + m
+			if menuError != "" {
+				gui.LabelEx(rl.NewRectangle(m, cy, w-2*m, row), menuError, rl.Red, rl.Blank, rl.Blank)
+				cy += row + m
+			}
+			cy += m
+			start := gui.Button(rl.NewRectangle(m, cy, w-2*m, row), "Start")
+			cy += row + m/2
+			if gui.Button(rl.NewRectangle(m, cy, w-2*m, row), "High scores") {
+				highScores = true
+			}
+			cy += row + m/2
+			dailyPressed := gui.Button(rl.NewRectangle(m, cy, w-2*m, row), getDailyLabel(g.dailyHistory))
+			cy += row + m
+			if start {
+				c.daily = ""
+				c.seed, _ = strconv.ParseUint(seedText, 10, 64)
+				c.code, c.board = nil, nil
+				menuError = ""
+				if codeText != "" {
+					shared, err := engine.ParseCode(codeText)
+					if err != nil {
+						menuError = "Invalid code"
+						start = false
+					} else {
+						c.width, c.height, c.bombs = shared.Width, shared.Height, shared.Bombs
+						c.noGuess, c.firstClick = shared.NoGuess, shared.FirstClick
+						c.code = &shared
+					}
+				}
+				if boardText != "" && start {
+					board, err := loadBoardFile(boardText)
+					if err != nil {
+						menuError = "Invalid board file"
+						start = false
+					} else {
+						c.setBoard(board)
+					}
+				}
+			}
+			if dailyPressed {
+				if err := c.setDaily(daily.Date(time.Now())); err != nil {
+					log.Println(err)
+				} else {
+					start = true
+				}
+			}
 			if start {
 				g.c = c
+				g.audio.SetVolume(c.volume)
+				if c.daily == "" {
+					// the fixed difficulty of the daily game is not a setting
+					if err := c.getSettings().Save(settingsFile); err != nil {
+						log.Println(err)
+					}
+				}
 				g.restart()
 				width, height := g.getSize()
 				rl.SetWindowSize(g.c.scale*width, g.c.scale*height)
 				rl.SetWindowPosition((rl.GetMonitorWidth(0)-g.c.scale*width)/2, (rl.GetMonitorHeight(0)-g.c.scale*height)/2)
 				menu = false
+			} else if int(cy) != rl.GetScreenHeight() {
+				rl.SetWindowSize(int(w), int(cy))
 			}
 		} else {
 			g.Update(g.c.scale)
+			// the board size may be changed by the "preset" action
+			gw, gh := g.getSize()
+			if rl.GetScreenWidth() != g.c.scale*gw || rl.GetScreenHeight() != g.c.scale*gh {
+				rl.SetWindowSize(g.c.scale*gw, g.c.scale*gh)
+			}
 			g.Draw(g.c.scale)
 		}
 		rl.EndDrawing()
 	}
 
+	if !menu {
+		g.saveGame()
+	}
+	g.saveReplay()
+	if !menu && g.playback == nil && g.c.daily == "" {
+		// keep the mute that can be toggled during the game
+		if err := g.c.getSettings().Save(settingsFile); err != nil {
+			log.Println(err)
+		}
+	}
+	g.audio.Close()
 	rl.CloseWindow()
 }
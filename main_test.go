@@ -0,0 +1,326 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mevdschee/raylib-go-mines/audio"
+	"github.com/mevdschee/raylib-go-mines/clips"
+	"github.com/mevdschee/raylib-go-mines/clocks"
+	"github.com/mevdschee/raylib-go-mines/engine"
+	"github.com/mevdschee/raylib-go-mines/replays"
+)
+
+// testStart is the time the clock of a test game starts at
+var testStart = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+// testGame is a game without a window that records the sounds it plays
+type testGame struct {
+	*game
+	t      *testing.T
+	sounds *audio.Recording
+	manual *clocks.Manual
+}
+
+// newTestGame creates a game without a window, its files are written in a
+// temporary directory
+func newTestGame(t *testing.T, c config) *testGame {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir+"/config")
+	t.Setenv("XDG_DATA_HOME", dir+"/data")
+	if c.holding == 0 {
+		c.holding = 15
+	}
+	sounds := audio.NewRecording()
+	player := audio.New(sounds)
+	bank := audio.Bank{}
+	for _, cue := range audio.Cues {
+		bank[cue] = cue + ".wav"
+	}
+	if err := player.Load(bank); err != nil {
+		t.Fatal(err)
+	}
+	manual := clocks.NewManual(testStart)
+	g := newGame(c, player, manual)
+	g.setMovie(nil, nil)
+	g.setHandlers()
+	g.restart()
+	return &testGame{game: g, t: t, sounds: sounds, manual: manual}
+}
+
+// newTestBoard creates a board with bombs at the given cells (x,y pairs)
+func newTestBoard(width, height int, bombs ...int) *engine.Board {
+	b := engine.NewBoard(width, height)
+	for i := 0; i+1 < len(bombs); i += 2 {
+		b.SetBomb(bombs[i], bombs[i+1], true)
+	}
+	return b
+}
+
+// boardConfig creates the config of a game on a board with an exact layout
+func boardConfig(b *engine.Board) config {
+	c := config{scale: 1, firstClick: engine.FirstClickSafe}
+	c.setBoard(b)
+	return c
+}
+
+// getPointer gets the pointer in the middle of a tile
+func getPointer(x, y int) clips.Pointer {
+	return clips.Pointer{X: float32(12 + x*16 + 8), Y: float32(55 + y*16 + 8)}
+}
+
+// input updates the game with the pointer like a frame of the game loop
+func (tg *testGame) input(p clips.Pointer) {
+	tg.recordPointer(p)
+	if err := tg.update(p); err != nil {
+		tg.t.Fatal(err)
+	}
+}
+
+// step advances the game one tick of the fixed timestep
+func (tg *testGame) step() {
+	tg.manual.Advance(tick)
+	tg.Tick()
+}
+
+// click presses and releases the left button on a tile
+func (tg *testGame) click(x, y int) {
+	p := getPointer(x, y)
+	p.Pressed = true
+	tg.input(p)
+	tg.step()
+	p.Pressed, p.Released = false, true
+	tg.input(p)
+	tg.step()
+}
+
+// rightClick presses the right button on a tile
+func (tg *testGame) rightClick(x, y int) {
+	p := getPointer(x, y)
+	p.RightPressed = true
+	tg.input(p)
+	tg.step()
+}
+
+func TestSoundCues(t *testing.T) {
+	// a 3x3 board with a bomb in the bottom right corner
+	g := newTestGame(t, boardConfig(newTestBoard(3, 3, 2, 2)))
+	g.click(1, 1)
+	g.click(2, 2)
+	if g.state != stateLost {
+		t.Fatalf("expected the game to be lost")
+	}
+	g.restart()
+	g.rightClick(2, 2)
+	g.click(0, 0)
+	if g.state != stateWon {
+		t.Fatalf("expected the game to be won")
+	}
+	expected := []string{audio.CueReveal, audio.CueExplode, audio.CueFlag, audio.CueFloodFill, audio.CueWin}
+	if !reflect.DeepEqual(g.sounds.Played, expected) {
+		t.Errorf("expected cues %v, got %v", expected, g.sounds.Played)
+	}
+}
+
+func TestMutedPlaysNothing(t *testing.T) {
+	g := newTestGame(t, boardConfig(newTestBoard(3, 3, 2, 2)))
+	g.audio.SetMuted(true)
+	g.click(0, 0)
+	if len(g.sounds.Played) != 0 {
+		t.Errorf("expected no cues when muted, got %v", g.sounds.Played)
+	}
+}
+
+func TestPresetAction(t *testing.T) {
+	g := newTestGame(t, boardConfig(newTestBoard(9, 9, 0, 0)))
+	g.click(8, 8)
+	buttons, err := g.movie.ButtonsFromJSON(`[{"label":"Intermediate","onPress":"call('preset', 16, 16, 40)"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buttons[0].Press()
+	// the preset applies after the movie is updated
+	g.input(getPointer(0, 0))
+	if g.c.width != 16 || g.c.height != 16 || g.c.bombs != 40 || g.c.board != nil {
+		t.Fatalf("expected a random 16x16 board with 40 bombs, got %dx%d with %d", g.c.width, g.c.height, g.c.bombs)
+	}
+	if g.state != stateWaiting || len(g.tiles) != 16 || len(g.getClips("icons")) != 16*16 {
+		t.Fatalf("expected a new game with 16x16 tiles")
+	}
+	g.click(15, 15)
+	if g.state != statePlaying && g.state != stateWon {
+		t.Errorf("expected the game on the new board to start, got state %d", g.state)
+	}
+}
+
+// findCode finds a shared code of a 9x9 game with 10 bombs whose first click
+// (in the middle) hits a bomb or not
+func findCode(t *testing.T, firstClick engine.FirstClick, bomb bool) engine.Code {
+	t.Helper()
+	for seed := uint64(1); seed < 1000; seed++ {
+		code := engine.Code{Width: 9, Height: 9, Bombs: 10, Seed: seed, X: 4, Y: 4, FirstClick: firstClick}
+		if code.Generate().IsBomb(4, 4) == bomb {
+			return code
+		}
+	}
+	t.Fatalf("no code found")
+	return engine.Code{}
+}
+
+func TestSharedCodeFirstClickLoses(t *testing.T) {
+	code := findCode(t, engine.FirstClickClassic, true)
+	g := newTestGame(t, config{scale: 1, width: 9, height: 9, bombs: 10, firstClick: engine.FirstClickClassic, code: &code})
+	if g.state != stateLost {
+		t.Fatalf("expected the first click of the code to lose, got state %d", g.state)
+	}
+	if g.clicks.Left != 1 || len(g.history) != 1 {
+		t.Errorf("expected the first click to be counted and in the history, got %d clicks and %d moves", g.clicks.Left, len(g.history))
+	}
+	expected := []string{audio.CueExplode}
+	if !reflect.DeepEqual(g.sounds.Played, expected) {
+		t.Errorf("expected cues %v, got %v", expected, g.sounds.Played)
+	}
+}
+
+func TestSharedCodeFirstClickIsAMove(t *testing.T) {
+	code := findCode(t, engine.FirstClickSafe, false)
+	g := newTestGame(t, config{scale: 1, width: 9, height: 9, bombs: 10, firstClick: engine.FirstClickSafe, code: &code})
+	if g.state != statePlaying && g.state != stateWon {
+		t.Fatalf("expected the game to be started, got state %d", g.state)
+	}
+	if !g.tiles[4][4].open || g.clicks.Left != 1 || len(g.history) != 1 {
+		t.Fatalf("expected the first click to open the tile as a move")
+	}
+	x, y := 12+4*16+8, 55+4*16+8
+	expected := []replays.Event{{Tick: 0, Action: replays.ActionPress, X: x, Y: y}, {Tick: 0, Action: replays.ActionRelease, X: x, Y: y}}
+	if !reflect.DeepEqual(g.replay.Events, expected) {
+		t.Errorf("expected events %v, got %v", expected, g.replay.Events)
+	}
+	if !g.undo() || g.state != statePlaying || g.tiles[4][4].open {
+		t.Errorf("expected the first click to be undone")
+	}
+}
+
+// practiceConfig creates the config of a practice game on a board with an exact layout
+func practiceConfig(b *engine.Board) config {
+	c := boardConfig(b)
+	c.practice = true
+	return c
+}
+
+func TestPracticeMineHitPauses(t *testing.T) {
+	// a 3x3 board with bombs in the top right and bottom right corner
+	g := newTestGame(t, practiceConfig(newTestBoard(3, 3, 2, 0, 2, 2)))
+	g.click(0, 0)
+	g.click(2, 2)
+	if g.state != stateHit || g.stats != nil || g.replay == nil {
+		t.Fatalf("expected a paused game that is not finished, got state %d", g.state)
+	}
+	// no moves until the mine hit is undone
+	g.click(2, 1)
+	if g.tiles[1][2].open {
+		t.Fatalf("expected the tile to stay closed")
+	}
+	for i := 0; i < 10*ticksPerSecond; i++ {
+		g.step()
+	}
+	if !g.undo() || g.state != statePlaying || g.tiles[2][2].open {
+		t.Fatalf("expected the mine hit to be undone")
+	}
+	// the game started at the first release and was paused at the second, the
+	// 10 seconds of the pause do not count
+	if elapsed := g.clock.Now().UnixNano() - g.time; elapsed != int64(2*tick) {
+		t.Errorf("expected %v elapsed, got %v", 2*tick, time.Duration(elapsed))
+	}
+	g.click(2, 1)
+	if g.state != stateWon || g.stats == nil {
+		t.Fatalf("expected the game to be won, got state %d", g.state)
+	}
+	expected := []string{audio.CueFloodFill, audio.CueExplode, audio.CueReveal, audio.CueWin}
+	if !reflect.DeepEqual(g.sounds.Played, expected) {
+		t.Errorf("expected cues %v, got %v", expected, g.sounds.Played)
+	}
+}
+
+func TestPracticeUndoKeepsStartTime(t *testing.T) {
+	// a 3x3 board with bombs in the top left and bottom right corner
+	g := newTestGame(t, practiceConfig(newTestBoard(3, 3, 0, 0, 2, 2)))
+	g.click(0, 2)
+	start := g.time
+	if !g.undo() || g.state != statePlaying || g.tiles[2][0].open {
+		t.Fatalf("expected the first move to be undone")
+	}
+	for i := 0; i < 3; i++ {
+		g.step()
+	}
+	if g.time != start {
+		t.Fatalf("expected the start time to be kept after undo")
+	}
+	g.click(2, 0)
+	g.click(0, 2)
+	if g.time != start || g.state != stateWon {
+		t.Errorf("expected the game to be won with the same start time, got state %d", g.state)
+	}
+}
+
+// getIcon gets the frame of the icon of a tile after the next frame of the game loop
+func (tg *testGame) getIcon(x, y int) int {
+	tg.input(getPointer(x, y))
+	return tg.getClips("icons")[y*tg.c.width+x].GetFrame()
+}
+
+func TestQuestionMarks(t *testing.T) {
+	// a 3x3 board with a bomb in the bottom right corner
+	c := boardConfig(newTestBoard(3, 3, 2, 2))
+	c.questions = true
+	g := newTestGame(t, c)
+	g.click(1, 1)
+	// flag, question mark and none
+	g.rightClick(2, 2)
+	if !g.tiles[2][2].marked || g.bombs != 0 || g.getIcon(2, 2) != iconMarked {
+		t.Fatalf("expected a flag")
+	}
+	g.rightClick(2, 2)
+	if g.tiles[2][2].marked || !g.tiles[2][2].question || g.bombs != 1 || g.getIcon(2, 2) != iconQuestionMark {
+		t.Fatalf("expected a question mark that is not counted as a flag")
+	}
+	// a chord does not count the question mark as a flag
+	g.hold(1, 1, 20)
+	if g.tiles[2][2].open || g.tiles[0][0].open || g.state != statePlaying {
+		t.Fatalf("expected the chord to open nothing")
+	}
+	g.rightClick(2, 2)
+	if g.tiles[2][2].marked || g.tiles[2][2].question || g.bombs != 1 || g.getIcon(2, 2) != iconClosed {
+		t.Fatalf("expected no mark")
+	}
+	// a question marked tile can be revealed
+	g.rightClick(0, 0)
+	g.rightClick(0, 0)
+	if !g.tiles[0][0].question {
+		t.Fatalf("expected a question mark")
+	}
+	g.click(0, 0)
+	if !g.tiles[0][0].open || g.tiles[0][0].question || g.state != stateWon {
+		t.Errorf("expected the question marked tile to open and win the game, got state %d", g.state)
+	}
+}
+
+func TestQuestionMarksOff(t *testing.T) {
+	// a 3x3 board with a bomb in the bottom right corner
+	g := newTestGame(t, boardConfig(newTestBoard(3, 3, 2, 2)))
+	g.click(1, 1)
+	g.rightClick(2, 2)
+	g.rightClick(2, 2)
+	if g.tiles[2][2].marked || g.tiles[2][2].question || g.bombs != 1 || g.getIcon(2, 2) != iconClosed {
+		t.Fatalf("expected the flag to be removed without a question mark")
+	}
+	// a chord counts a flag
+	g.rightClick(2, 2)
+	g.hold(1, 1, 20)
+	if g.state != stateWon {
+		t.Errorf("expected the chord to open the tiles and win the game, got state %d", g.state)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+const (
+	moveReveal = iota
+	moveFlag
+	moveChord
+)
+
+// change is a tile before and after a move
+type change struct {
+	index  int
+	before tile
+	after  tile
+}
+
+// status is the part of the game state that a move changes besides the tiles
+type status struct {
+	state  int
+	button int
+	bombs  int
+	closed int
+}
+
+// move is a command with the changes it made, so it can be undone and redone
+type move struct {
+	kind    int
+	x, y    int
+	changes []change
+	before  status
+	after   status
+}
+
+func (g *game) getStatus() status {
+	return status{state: g.state, button: g.button, bombs: g.bombs, closed: g.closed}
+}
+
+func (g *game) setStatus(s status) {
+	g.state, g.button, g.bombs, g.closed = s.state, s.button, s.bombs, s.closed
+}
+
+// play runs a move and records the tiles it changes
+func (g *game) play(kind, x, y int, do func()) {
+	g.recording = &move{kind: kind, x: x, y: y, before: g.getStatus()}
+	do()
+	g.checkWon()
+	m := g.recording
+	if g.state != m.before.state && (g.state == stateWon || g.state == stateLost) {
+		g.finish()
+	}
+	g.recording = nil
+	if len(m.changes) == 0 {
+		return
+	}
+	for i := range m.changes {
+		c := &m.changes[i]
+		c.after = g.tiles[c.index/g.c.width][c.index%g.c.width]
+		c.after.pressed = false
+	}
+	m.after = g.getStatus()
+	g.history = append(g.history, m)
+	g.future = g.future[:0]
+}
+
+// touch records a tile before the move changes it
+func (g *game) touch(x, y int) {
+	if g.recording == nil {
+		return
+	}
+	index := y*g.c.width + x
+	for _, c := range g.recording.changes {
+		if c.index == index {
+			return
+		}
+	}
+	before := g.tiles[y][x]
+	before.pressed = false
+	g.recording.changes = append(g.recording.changes, change{index: index, before: before})
+}
+
+// undo reverts the last move, a won game can not be undone
+func (g *game) undo() bool {
+	if len(g.history) == 0 || g.state == stateWon {
+		return false
+	}
+	m := g.history[len(g.history)-1]
+	g.history = g.history[:len(g.history)-1]
+	for _, c := range m.changes {
+		g.tiles[c.index/g.c.width][c.index%g.c.width] = c.before
+	}
+	if g.state == stateHit {
+		// the time that the game was paused does not count
+		g.time += g.clock.Now().UnixNano() - g.hitAt
+	}
+	before := m.before
+	if before.state == stateWaiting {
+		// the game keeps its start time (and daily attempt) when the first move is undone
+		before.state = statePlaying
+	}
+	g.setStatus(before)
+	g.stats = nil
+	g.future = append(g.future, m)
+	g.undos++
+	return true
+}
+
+// redo plays the last undone move again
+func (g *game) redo() bool {
+	if len(g.future) == 0 {
+		return false
+	}
+	m := g.future[len(g.future)-1]
+	g.future = g.future[:len(g.future)-1]
+	for _, c := range m.changes {
+		g.tiles[c.index/g.c.width][c.index%g.c.width] = c.after
+	}
+	g.setStatus(m.after)
+	g.history = append(g.history, m)
+	if g.state == stateHit {
+		g.hitAt = g.clock.Now().UnixNano()
+	}
+	if g.state == stateWon || g.state == stateLost {
+		g.finish()
+	}
+	return true
+}
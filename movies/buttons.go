@@ -0,0 +1,47 @@
+package movies
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mevdschee/raylib-go-mines/layers"
+)
+
+// ButtonJSON is a text button in JSON, e.g. of a menu that is drawn with a
+// GUI library, with a handler like the clips have
+type ButtonJSON struct {
+	Label   string `json:"label" schema:"required"`
+	OnPress string `json:"onPress" schema:"handler,required"`
+}
+
+// Button is a text button with a compiled handler
+type Button struct {
+	Label   string
+	onPress func()
+}
+
+// Press runs the handler of the button
+func (b Button) Press() {
+	if b.onPress != nil {
+		b.onPress()
+	}
+}
+
+// ButtonsFromJSON creates text buttons with handlers that emit the events and
+// call the actions of the movie
+func (m *Movie) ButtonsFromJSON(data string) ([]Button, error) {
+	buttonJSONs := []ButtonJSON{}
+	err := json.Unmarshal([]byte(data), &buttonJSONs)
+	if err != nil {
+		return nil, err
+	}
+	buttons := []Button{}
+	for _, b := range buttonJSONs {
+		fn, err := layers.Handler(b.OnPress, m.bus.Functions())
+		if err != nil {
+			return nil, fmt.Errorf("Handler of button '%s': %v", b.Label, err)
+		}
+		buttons = append(buttons, Button{Label: b.Label, onPress: fn})
+	}
+	return buttons, nil
+}
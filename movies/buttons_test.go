@@ -0,0 +1,45 @@
+package movies
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestButtonsFromJSON(t *testing.T) {
+	m := New()
+	called := [][]interface{}{}
+	m.RegisterAction("preset", func(args ...interface{}) {
+		called = append(called, args)
+	})
+	emitted := 0
+	m.Subscribe("restart", func() {
+		emitted++
+	})
+	buttons, err := m.ButtonsFromJSON(`[{"label":"Intermediate","onPress":"call('preset', 16, 16, 40)"},
+		{"label":"Restart","onPress":"emit('restart')"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buttons) != 2 || buttons[0].Label != "Intermediate" || buttons[1].Label != "Restart" {
+		t.Fatalf("unexpected buttons %+v", buttons)
+	}
+	buttons[0].Press()
+	buttons[1].Press()
+	expected := [][]interface{}{{16, 16, 40}}
+	if !reflect.DeepEqual(called, expected) {
+		t.Errorf("expected action arguments %v, got %v", expected, called)
+	}
+	if emitted != 1 {
+		t.Errorf("expected the event to be emitted once, got %d", emitted)
+	}
+}
+
+func TestButtonsFromJSONErrors(t *testing.T) {
+	m := New()
+	if _, err := m.ButtonsFromJSON(`[{"label":"Broken","onPress":"call('preset',"}]`); err == nil {
+		t.Errorf("expected a syntax error in the handler")
+	}
+	if _, err := m.ButtonsFromJSON(`{}`); err == nil {
+		t.Errorf("expected an error for a document that is not an array")
+	}
+}
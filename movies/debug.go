@@ -0,0 +1,119 @@
+package movies
+
+import (
+	"fmt"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/mevdschee/raylib-go-mines/clips"
+	"github.com/mevdschee/raylib-go-mines/scenes"
+)
+
+// debug is the state of the debug overlay
+type debug struct {
+	updates map[string]time.Duration
+	draws   map[string]time.Duration
+	pointer clips.Pointer
+}
+
+// SetDebug shows or hides the debug overlay with clip bounds, names, frames and timings
+func (m *Movie) SetDebug(enabled bool) {
+	if !enabled {
+		m.debug = nil
+		return
+	}
+	if m.debug == nil {
+		m.debug = &debug{
+			updates: map[string]time.Duration{},
+			draws:   map[string]time.Duration{},
+		}
+	}
+}
+
+// IsDebug returns whether or not the debug overlay is shown
+func (m *Movie) IsDebug() bool {
+	return m.debug != nil
+}
+
+func (d *debug) update(scene *scenes.Scene, p clips.Pointer) error {
+	// the clips are hit-tested with the pointer they were updated with (e.g. from a replay)
+	d.pointer = p
+	layers := scene.GetLayers()
+	for _, name := range scene.GetOrder() {
+		start := time.Now()
+		err := layers[name].Update(p)
+		d.updates[name] = time.Since(start)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *debug) draw(scene *scenes.Scene, scale int) {
+	s := float32(scale)
+	layers := scene.GetLayers()
+	for _, name := range scene.GetOrder() {
+		start := time.Now()
+		layers[name].Draw(scale)
+		d.draws[name] = time.Since(start)
+	}
+	for _, name := range scene.GetOrder() {
+		counts := map[string]int{}
+		for _, clip := range layers[name].GetClips() {
+			i := counts[clip.GetName()]
+			counts[clip.GetName()]++
+			b := clip.GetBounds()
+			r := rl.NewRectangle(b.X*s, b.Y*s, b.Width*s, b.Height*s)
+			rl.DrawRectangleLinesEx(r, 1, rl.Fade(rl.Magenta, 0.6))
+			label := getLabel(clip, i)
+			if b.Width*s >= float32(rl.MeasureText(label, 10)) {
+				rl.DrawText(label, int32(r.X)+2, int32(r.Y)+2, 10, rl.Magenta)
+			}
+		}
+	}
+	hovered, hoveredLabel := d.getHovered(scene)
+	y := int32(2)
+	rl.DrawText(fmt.Sprintf("%.0f fps", rl.GetFPS()), 2, y, 10, rl.DarkGreen)
+	for _, name := range scene.GetOrder() {
+		y += 11
+		text := fmt.Sprintf("%s u:%.2fms d:%.2fms", name, ms(d.updates[name]), ms(d.draws[name]))
+		rl.DrawText(text, 2, y, 10, rl.DarkGreen)
+	}
+	if hovered != nil {
+		b := hovered.GetBounds()
+		r := rl.NewRectangle(b.X*s, b.Y*s, b.Width*s, b.Height*s)
+		rl.DrawRectangleRec(r, rl.Fade(rl.Yellow, 0.4))
+		rl.DrawRectangleLinesEx(r, 2, rl.Red)
+		text := fmt.Sprintf("%s (%.0f,%.0f %.0fx%.0f)", hoveredLabel, b.X, b.Y, b.Width, b.Height)
+		rl.DrawText(text, 2, int32(rl.GetScreenHeight())-12, 10, rl.Red)
+	}
+}
+
+// getLabel gets the label of a clip with its index in the series of clips with the same name
+func getLabel(clip *clips.Clip, i int) string {
+	return fmt.Sprintf("%s[%d]:%d", clip.GetName(), i, clip.GetFrame())
+}
+
+// getHovered finds the topmost clip under the pointer and its label
+func (d *debug) getHovered(scene *scenes.Scene) (*clips.Clip, string) {
+	var hovered *clips.Clip
+	hoveredLabel := ""
+	layers := scene.GetLayers()
+	for _, name := range scene.GetOrder() {
+		counts := map[string]int{}
+		for _, clip := range layers[name].GetClips() {
+			i := counts[clip.GetName()]
+			counts[clip.GetName()]++
+			if clip.Contains(d.pointer) {
+				hovered = clip
+				hoveredLabel = name + "/" + getLabel(clip, i)
+			}
+		}
+	}
+	return hovered, hoveredLabel
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
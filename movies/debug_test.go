@@ -0,0 +1,47 @@
+package movies
+
+import (
+	"testing"
+
+	"github.com/mevdschee/raylib-go-mines/clips"
+	"github.com/mevdschee/raylib-go-mines/sprites"
+)
+
+const testSprites = `[{"name":"icons","x":0,"y":0,"width":16,"height":16,"count":17,"grid":9}]`
+
+const testScenes = `[{"name":"game","layers":[
+	{"name":"bg","clips":[{"sprite":"icons","name":"back","x":"0","y":"0","width":"w*16","height":"h*16"}]},
+	{"name":"fg","clips":[{"sprite":"icons","name":"icons","repeat":"w*h","x":"(i%w)*16","y":"floor(i/w)*16"}]}
+]}]`
+
+func newTestMovie(t *testing.T) *Movie {
+	t.Helper()
+	spriteMap, err := sprites.NewSpriteMap(nil, testSprites)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := FromJSON(spriteMap, testScenes, map[string]interface{}{"w": 3, "h": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestDebugHoveredUsesPointer(t *testing.T) {
+	m := newTestMovie(t)
+	m.SetDebug(true)
+	// the pointer of a replay on the last icon, not the mouse
+	if err := m.UpdatePointer(clips.Pointer{X: 40, Y: 20}); err != nil {
+		t.Fatal(err)
+	}
+	clip, label := m.debug.getHovered(m.currentScene)
+	if clip == nil || label != "fg/icons[5]:0" {
+		t.Errorf("expected the last icon to be hovered, got '%s'", label)
+	}
+	if err := m.UpdatePointer(clips.Pointer{X: 100, Y: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if clip, _ := m.debug.getHovered(m.currentScene); clip != nil {
+		t.Errorf("expected no clip to be hovered outside of the movie")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/mevdschee/raylib-go-mines/clips"
+	"github.com/mevdschee/raylib-go-mines/clocks"
+	"github.com/mevdschee/raylib-go-mines/events"
 	"github.com/mevdschee/raylib-go-mines/scenes"
 	"github.com/mevdschee/raylib-go-mines/sprites"
 )
@@ -13,6 +15,8 @@ import (
 type Movie struct {
 	currentScene *scenes.Scene
 	scenes       map[string]*scenes.Scene
+	bus          *events.Bus
+	debug        *debug
 }
 
 // New creates a new movie
@@ -20,6 +24,7 @@ func New() *Movie {
 	return &Movie{
 		currentScene: nil,
 		scenes:       map[string]*scenes.Scene{},
+		bus:          events.New(),
 	}
 }
 
@@ -33,9 +38,19 @@ func FromJSON(spriteMap sprites.SpriteMap, data string, parameters map[string]in
 	movie := Movie{
 		currentScene: &scenes.Scene{},
 		scenes:       map[string]*scenes.Scene{},
+		bus:          events.New(),
+	}
+	// the built-in functions are added to a copy, the map of the caller may be
+	// used for other movies
+	env := map[string]interface{}{}
+	for name, value := range parameters {
+		env[name] = value
+	}
+	for name, fn := range movie.bus.Functions() {
+		env[name] = fn
 	}
 	for _, sceneJSON := range sceneJSONs {
-		scene, err := scenes.FromJSON(spriteMap, sceneJSON, parameters)
+		scene, err := scenes.FromJSON(spriteMap, sceneJSON, env)
 		if err != nil {
 			return nil, err
 		}
@@ -52,19 +67,65 @@ func (m *Movie) Add(scene *scenes.Scene) {
 	}
 }
 
+// Subscribe adds a function that is called when a clip handler emits the event
+func (m *Movie) Subscribe(event string, fn func()) {
+	m.bus.Subscribe(event, fn)
+}
+
+// RegisterAction registers a function that clip handlers can call by name
+func (m *Movie) RegisterAction(name string, fn func(args ...interface{})) {
+	m.bus.RegisterAction(name, fn)
+}
+
+// Emit emits an event as if a clip handler emitted it
+func (m *Movie) Emit(event string) {
+	m.bus.Emit(event)
+}
+
 // Draw draws the movie
 func (m *Movie) Draw(scale int) {
-	if m.currentScene != nil {
-		m.currentScene.Draw(scale)
+	if m.currentScene == nil {
+		return
 	}
+	if m.debug != nil {
+		m.debug.draw(m.currentScene, scale)
+		return
+	}
+	m.currentScene.Draw(scale)
 }
 
-// Update updates the movie
+// Update updates the movie with the mouse
 func (m *Movie) Update(scale int) (err error) {
+	return m.UpdatePointer(clips.ReadMouse(scale))
+}
+
+// UpdatePointer updates the movie with the pointer, e.g. from a replay
+func (m *Movie) UpdatePointer(p clips.Pointer) (err error) {
+	if m.currentScene == nil {
+		return nil
+	}
+	if m.debug != nil {
+		return m.debug.update(m.currentScene, p)
+	}
+	return m.currentScene.Update(p)
+}
+
+// Tick advances the current scene to the time of the clock
+func (m *Movie) Tick() {
 	if m.currentScene != nil {
-		err = m.currentScene.Update(scale)
+		m.currentScene.Tick()
+	}
+}
+
+// SetClock sets the clock of all clips in the movie
+func (m *Movie) SetClock(clock clocks.Clock) {
+	for _, scene := range m.scenes {
+		for _, layer := range scene.GetLayers() {
+			for _, clip := range layer.GetClips() {
+				clip.SetClock(clock)
+			}
+		}
 	}
-	return err
 }
 
 // GetClip gets a clip from the movie
@@ -0,0 +1,25 @@
+package movies
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mevdschee/raylib-go-mines/sprites"
+)
+
+func TestFromJSONKeepsParameters(t *testing.T) {
+	spriteMap, err := sprites.NewSpriteMap(nil, testSprites)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parameters := map[string]interface{}{"w": 3, "h": 2}
+	for i := 0; i < 2; i++ {
+		if _, err := FromJSON(spriteMap, testScenes, parameters); err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]interface{}{"w": 3, "h": 2}
+		if !reflect.DeepEqual(parameters, expected) {
+			t.Fatalf("expected the parameters %v to be unchanged, got %v", expected, parameters)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package movies
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SceneTree is a scene in the movie tree
+type SceneTree struct {
+	Name    string      `json:"name"`
+	Current bool        `json:"current"`
+	Layers  []LayerTree `json:"layers"`
+}
+
+// LayerTree is a layer in the movie tree
+type LayerTree struct {
+	Name  string     `json:"name"`
+	Clips []ClipTree `json:"clips"`
+}
+
+// ClipTree is a clip in the movie tree
+type ClipTree struct {
+	Name   string  `json:"name"`
+	Index  int     `json:"index"`
+	Frame  int     `json:"frame"`
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+}
+
+// Tree gets the scenes, layers and clips of the movie with their frames and positions
+func (m *Movie) Tree() []SceneTree {
+	names := []string{}
+	for name := range m.scenes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tree := []SceneTree{}
+	for _, name := range names {
+		scene := m.scenes[name]
+		sceneTree := SceneTree{
+			Name:    name,
+			Current: scene == m.currentScene,
+			Layers:  []LayerTree{},
+		}
+		layers := scene.GetLayers()
+		for _, layerName := range scene.GetOrder() {
+			layerTree := LayerTree{Name: layerName, Clips: []ClipTree{}}
+			counts := map[string]int{}
+			for _, clip := range layers[layerName].GetClips() {
+				b := clip.GetBounds()
+				layerTree.Clips = append(layerTree.Clips, ClipTree{
+					Name:   clip.GetName(),
+					Index:  counts[clip.GetName()],
+					Frame:  clip.GetFrame(),
+					X:      b.X,
+					Y:      b.Y,
+					Width:  b.Width,
+					Height: b.Height,
+				})
+				counts[clip.GetName()]++
+			}
+			sceneTree.Layers = append(sceneTree.Layers, layerTree)
+		}
+		tree = append(tree, sceneTree)
+	}
+	return tree
+}
+
+// GotoFrame goes to a frame of the i-th clip with the name
+func (m *Movie) GotoFrame(scene, layer, clip string, i, frame int) error {
+	c, err := m.getClip(scene, layer, clip, i)
+	if err != nil {
+		return err
+	}
+	if frame < 0 {
+		return fmt.Errorf("GotoFrame: invalid frame %d", frame)
+	}
+	c.GotoFrame(frame)
+	return nil
+}
@@ -0,0 +1,85 @@
+package particles
+
+import (
+	"math"
+	"testing"
+)
+
+var testConfig = Config{
+	Frames:    []int{1, 2, 3},
+	Rate:      20,
+	Lifetime:  [2]float64{0.5, 1.2},
+	VelocityX: [2]float64{-120, 120},
+	VelocityY: [2]float64{-160, 40},
+	Gravity:   [2]float64{200, 300},
+	Fade:      true,
+	Seed:      1,
+}
+
+// golden are the particles of the test config after a burst of 3 at (100,50)
+// and emitting from (10,20) for 15 steps of 1/30 s
+var golden = []Particle{
+	{X: 129.493811, Y: 99.691732, Frame: 3},
+	{X: 145.281842, Y: 52.780167, Frame: 3},
+	{X: 112.650444, Y: 49.228177, Frame: 1},
+	{X: 26.277480, Y: 52.719279, Frame: 3},
+	{X: -30.184193, Y: 14.070673, Frame: 2},
+	{X: 11.365751, Y: 28.664013, Frame: 2},
+	{X: 16.234850, Y: 8.025089, Frame: 2},
+	{X: 12.794931, Y: 28.511352, Frame: 3},
+	{X: 19.992604, Y: 3.652304, Frame: 3},
+	{X: 2.977329, Y: 3.462543, Frame: 2},
+	{X: 1.158039, Y: 12.473789, Frame: 1},
+	{X: 11.712796, Y: 12.392477, Frame: 2},
+}
+
+func run(e *Emitter) []Particle {
+	e.Burst(100, 50, 3)
+	e.Start(10, 20)
+	for i := 0; i < 15; i++ {
+		e.Update(1.0 / 30)
+	}
+	return e.GetParticles()
+}
+
+func checkGolden(t *testing.T, particles []Particle) {
+	t.Helper()
+	if len(particles) != len(golden) {
+		t.Fatalf("expected %d particles, got %d", len(golden), len(particles))
+	}
+	for i, p := range particles {
+		g := golden[i]
+		if math.Abs(p.X-g.X) > 1e-5 || math.Abs(p.Y-g.Y) > 1e-5 || p.Frame != g.Frame {
+			t.Errorf("particle %d: expected (%f,%f) frame %d, got (%f,%f) frame %d", i, g.X, g.Y, g.Frame, p.X, p.Y, p.Frame)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	checkGolden(t, run(New(testConfig)))
+}
+
+func TestResetReplays(t *testing.T) {
+	e := New(testConfig)
+	run(e)
+	e.Reset()
+	if len(e.GetParticles()) != 0 {
+		t.Fatalf("expected no particles after reset")
+	}
+	checkGolden(t, run(e))
+}
+
+func TestLifetimeAndAlpha(t *testing.T) {
+	e := New(testConfig)
+	e.Burst(0, 0, 10)
+	e.Update(0.25)
+	for _, p := range e.GetParticles() {
+		if a := e.Alpha(p); a <= 0 || a >= 1 {
+			t.Errorf("expected alpha in (0,1), got %f", a)
+		}
+	}
+	e.Update(1)
+	if n := len(e.GetParticles()); n != 0 {
+		t.Errorf("expected all particles to be dead after their lifetime, got %d", n)
+	}
+}
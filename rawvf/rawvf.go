@@ -0,0 +1,259 @@
+package rawvf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mevdschee/raylib-go-mines/replays"
+)
+
+// the board of the game starts at (OffsetX, OffsetY) and has squares of Size pixels
+const (
+	OffsetX = 12
+	OffsetY = 55
+	Size    = 16
+)
+
+// events maps the RAW Vienna mouse events to replay actions, "mc" and "mr"
+// (middle button) are a chord, which is a left click on an open square
+var events = map[string]string{
+	"lc": replays.ActionPress,
+	"lr": replays.ActionRelease,
+	"rc": replays.ActionRightPress,
+	"mc": replays.ActionPress,
+	"mr": replays.ActionRelease,
+	"mv": replays.ActionMove,
+}
+
+// actions maps the replay actions to RAW Vienna mouse events
+var actions = map[string]string{
+	replays.ActionPress:      "lc",
+	replays.ActionRelease:    "lr",
+	replays.ActionRightPress: "rc",
+	replays.ActionMove:       "mv",
+}
+
+// Read converts a RAW Vienna replay (e.g. from Arbiter or Viennasweeper) into
+// a replay with the given number of ticks per second, the times are relative
+// to the start of the game and are shifted so that the first event (that may
+// be before time zero) is at tick zero at the earliest
+func Read(data []byte, ticksPerSecond int) (*replays.Replay, error) {
+	r := replays.Replay{
+		Version:        replays.Version,
+		TicksPerSecond: ticksPerSecond,
+		Mines:          []int{},
+		Events:         []replays.Event{},
+	}
+	header := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	section := "header"
+	row := 0
+	first := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		switch {
+		case line == "Board:":
+			var err error
+			r.Width, err = strconv.Atoi(header["Width"])
+			if err != nil || r.Width <= 0 {
+				return nil, fmt.Errorf("Read: invalid width '%s'", header["Width"])
+			}
+			r.Height, err = strconv.Atoi(header["Height"])
+			if err != nil || r.Height <= 0 {
+				return nil, fmt.Errorf("Read: invalid height '%s'", header["Height"])
+			}
+			r.Questions = strings.EqualFold(header["Marks"], "On")
+			section = "board"
+		case line == "Events:":
+			if row != r.Height {
+				return nil, fmt.Errorf("Read: expected %d board rows, got %d", r.Height, row)
+			}
+			section = "events"
+		case section == "header":
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) == 2 {
+				header[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+			}
+		case section == "board":
+			if row >= r.Height || len(line) != r.Width {
+				return nil, fmt.Errorf("Read: invalid board row '%s'", line)
+			}
+			for x := 0; x < r.Width; x++ {
+				if line[x] == '*' {
+					r.Mines = append(r.Mines, row*r.Width+x)
+				}
+			}
+			row++
+		case section == "events":
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+			seconds, err := strconv.ParseFloat(fields[0], 64)
+			if err != nil {
+				// lines without a time, e.g. comments
+				continue
+			}
+			tick := int(math.Round(seconds * float64(ticksPerSecond)))
+			if tick < first {
+				first = tick
+			}
+			switch fields[1] {
+			case "won":
+				r.Result = "won"
+			case "blast":
+				r.Result = "lost"
+			}
+			if tick > r.Ticks {
+				r.Ticks = tick
+			}
+			action, ok := events[fields[1]]
+			if !ok {
+				continue
+			}
+			x, y, ok := getPosition(fields[2:])
+			if !ok {
+				return nil, fmt.Errorf("Read: invalid event '%s'", line)
+			}
+			r.Add(tick, action, OffsetX+x, OffsetY+y)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if section != "events" {
+		return nil, fmt.Errorf("Read: missing board or events")
+	}
+	for i := range r.Events {
+		r.Events[i].Tick -= first
+	}
+	r.Ticks -= first
+	r.Bombs = len(r.Mines)
+	if mines, err := strconv.Atoi(header["Mines"]); err != nil || mines != r.Bombs {
+		return nil, fmt.Errorf("Read: expected %s mines, got %d", header["Mines"], r.Bombs)
+	}
+	if r.Result == "won" {
+		r.Closed = r.Bombs
+	}
+	// the number of closed squares of a lost game is not in the file
+	return &r, nil
+}
+
+// getPosition finds the pixel position "(x y)" in the fields of an event
+func getPosition(fields []string) (int, int, bool) {
+	for i := 0; i+1 < len(fields); i++ {
+		if !strings.HasPrefix(fields[i], "(") || !strings.HasSuffix(fields[i+1], ")") {
+			continue
+		}
+		x, err := strconv.Atoi(strings.TrimPrefix(fields[i], "("))
+		if err != nil {
+			return 0, 0, false
+		}
+		y, err := strconv.Atoi(strings.TrimSuffix(fields[i+1], ")"))
+		if err != nil {
+			return 0, 0, false
+		}
+		return x, y, true
+	}
+	return 0, 0, false
+}
+
+// getLevel gets the name of the level of the board size
+func getLevel(r *replays.Replay) string {
+	switch {
+	case r.Width == 9 && r.Height == 9 && r.Bombs == 10:
+		return "Beginner"
+	case r.Width == 16 && r.Height == 16 && r.Bombs == 40:
+		return "Intermediate"
+	case r.Width == 30 && r.Height == 16 && r.Bombs == 99:
+		return "Expert"
+	}
+	return "Custom"
+}
+
+// onBoard returns whether or not the event is a mouse event on the board
+func onBoard(r *replays.Replay, e replays.Event) bool {
+	_, ok := actions[e.Action]
+	x, y := e.X-OffsetX, e.Y-OffsetY
+	return ok && x >= 0 && y >= 0 && x < r.Width*Size && y < r.Height*Size
+}
+
+// Write converts a replay into a RAW Vienna replay with the recorded mines,
+// only the mouse events on the board are written, at the time of their tick
+// relative to the start of the game: the first release on the board (like
+// the timer of the game), so the press before it has a negative time
+func Write(r *replays.Replay) ([]byte, error) {
+	mines := make([]bool, r.Width*r.Height)
+	for _, i := range r.Mines {
+		if i < 0 || i >= len(mines) {
+			return nil, fmt.Errorf("Write: invalid mine %d", i)
+		}
+		mines[i] = true
+	}
+	start, end := -1, 0
+	for _, e := range r.Events {
+		if onBoard(r, e) && (start < 0 || e.Action == replays.ActionRelease) {
+			start = e.Tick
+			if e.Action == replays.ActionRelease {
+				break
+			}
+		}
+	}
+	lines := []string{}
+	for _, e := range r.Events {
+		if !onBoard(r, e) {
+			continue
+		}
+		x, y := e.X-OffsetX, e.Y-OffsetY
+		end = e.Tick
+		seconds := float64(e.Tick-start) / float64(r.TicksPerSecond)
+		lines = append(lines, fmt.Sprintf("%.3f %s %d %d (%d %d)", seconds, actions[e.Action], x/Size+1, y/Size+1, x, y))
+	}
+	// the game ends with the last event on the board
+	seconds := float64(end-start) / float64(r.TicksPerSecond)
+	if start < 0 {
+		seconds = 0
+	}
+	switch r.Result {
+	case "won":
+		lines = append(lines, fmt.Sprintf("%.3f won", seconds))
+	case "lost":
+		lines = append(lines, fmt.Sprintf("%.3f blast", seconds))
+	}
+	marks := "Off"
+	if r.Questions {
+		marks = "On"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "RawVF_Version: Rev2\n")
+	fmt.Fprintf(&b, "Program: Raylib Go Mines\n")
+	fmt.Fprintf(&b, "Level: %s\n", getLevel(r))
+	fmt.Fprintf(&b, "Width: %d\n", r.Width)
+	fmt.Fprintf(&b, "Height: %d\n", r.Height)
+	fmt.Fprintf(&b, "Mines: %d\n", r.Bombs)
+	fmt.Fprintf(&b, "Marks: %s\n", marks)
+	fmt.Fprintf(&b, "Time: %.3f\n", seconds)
+	fmt.Fprintf(&b, "Board:\n")
+	for y := 0; y < r.Height; y++ {
+		for x := 0; x < r.Width; x++ {
+			if mines[y*r.Width+x] {
+				b.WriteByte('*')
+			} else {
+				b.WriteByte('0')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	fmt.Fprintf(&b, "Events:\n")
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,150 @@
+package rawvf
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mevdschee/raylib-go-mines/replays"
+)
+
+const ticksPerSecond = 30
+
+func readFile(t *testing.T, fileName string) ([]byte, *replays.Replay) {
+	t.Helper()
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Read(data, ticksPerSecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data, r
+}
+
+func TestReadShiftsTimes(t *testing.T) {
+	_, r := readFile(t, "testdata/lost.rawvf")
+	if r.Width != 9 || r.Height != 9 || r.Bombs != 10 || !r.Questions {
+		t.Errorf("expected a 9x9 board with 10 mines and marks")
+	}
+	// the press at -0.067 s is at tick zero and the game starts 2 ticks later
+	expected := []replays.Event{
+		{Tick: 0, Action: replays.ActionPress, X: OffsetX + 72, Y: OffsetY + 72},
+		{Tick: 2, Action: replays.ActionRelease, X: OffsetX + 72, Y: OffsetY + 72},
+		{Tick: 15, Action: replays.ActionMove, X: OffsetX + 40, Y: OffsetY + 40},
+		{Tick: 44, Action: replays.ActionPress, X: OffsetX + 24, Y: OffsetY + 24},
+		{Tick: 45, Action: replays.ActionRelease, X: OffsetX + 24, Y: OffsetY + 24},
+	}
+	if !reflect.DeepEqual(r.Events, expected) {
+		t.Errorf("expected events %v, got %v", expected, r.Events)
+	}
+	if r.Ticks != 45 || r.Result != "lost" || r.Closed != 0 {
+		t.Errorf("expected a lost game of 45 ticks, got %s in %d", r.Result, r.Ticks)
+	}
+	mines := []int{0, 8, 10, 26, 30, 41, 54, 60, 72, 80}
+	if !reflect.DeepEqual(r.Mines, mines) {
+		t.Errorf("expected mines %v, got %v", mines, r.Mines)
+	}
+}
+
+func TestReadWon(t *testing.T) {
+	_, r := readFile(t, "testdata/won.rawvf")
+	if r.Result != "won" || r.Closed != 2 || r.Ticks != 48 || r.Events[0].Tick != 0 || r.Events[1].Tick != 3 {
+		t.Errorf("expected a won game started at tick 3 and won at 48, got %s from %d to %d", r.Result, r.Events[1].Tick, r.Ticks)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, fileName := range []string{"testdata/won.rawvf", "testdata/lost.rawvf"} {
+		data, r := readFile(t, fileName)
+		written, err := Write(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := Read(written, ticksPerSecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(again, r) {
+			t.Errorf("%s: expected the same replay after writing it, got %+v", fileName, again)
+		}
+		// the file of the game itself is written back as it is
+		if fileName == "testdata/won.rawvf" && string(written) != string(data) {
+			t.Errorf("%s: expected\n%s\ngot\n%s", fileName, data, written)
+		}
+	}
+}
+
+func TestWriteUsesRecordedMines(t *testing.T) {
+	_, r := readFile(t, "testdata/won.rawvf")
+	// the board is not generated from the code
+	r.Code = "invalid"
+	written, err := Write(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Read(written, ticksPerSecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.Mines, r.Mines) {
+		t.Errorf("expected mines %v, got %v", r.Mines, again.Mines)
+	}
+	r.Mines = []int{12}
+	if _, err := Write(r); err == nil {
+		t.Errorf("expected an error for a mine outside the board")
+	}
+}
+
+func TestWriteRelativeTimes(t *testing.T) {
+	r := &replays.Replay{
+		Version:        replays.Version,
+		TicksPerSecond: ticksPerSecond,
+		Width:          2,
+		Height:         1,
+		Bombs:          1,
+		Mines:          []int{1},
+		Result:         "won",
+		Ticks:          130,
+		Events: []replays.Event{
+			{Tick: 100, Action: replays.ActionPress, X: OffsetX + 8, Y: OffsetY + 8},
+			{Tick: 101, Action: replays.ActionHint},
+			{Tick: 103, Action: replays.ActionRelease, X: OffsetX + 8, Y: OffsetY + 8},
+			// the button of the face is not on the board
+			{Tick: 120, Action: replays.ActionPress, X: 40, Y: 20},
+		},
+	}
+	written, err := Write(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Read(written, ticksPerSecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(written), "Time: 0.000\n") || !strings.Contains(string(written), "\n-0.100 lc 1 1 (8 8)\n0.000 lr 1 1 (8 8)\n0.000 won\n") {
+		t.Errorf("expected the events relative to the first release, got\n%s", written)
+	}
+	// the ticks before the press are dropped
+	expected := []replays.Event{r.Events[0], r.Events[2]}
+	expected[0].Tick, expected[1].Tick = 0, 3
+	if !reflect.DeepEqual(again.Events, expected) {
+		t.Errorf("expected events %v, got %v", expected, again.Events)
+	}
+	if again.Ticks != 3 || again.Result != "won" {
+		t.Errorf("expected a won game of 3 ticks, got %s in %d", again.Result, again.Ticks)
+	}
+}
+
+func TestReadBeforeTimeZero(t *testing.T) {
+	data := []byte("Width: 2\nHeight: 1\nMines: 1\nBoard:\n0*\nEvents:\n-0.050 lc 1 1 (8 8)\n0.000 lr 1 1 (8 8)\n0.000 won\n")
+	r, err := Read(data, ticksPerSecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Events[0].Tick != 0 || r.Events[1].Tick != 2 || r.Ticks != 2 {
+		t.Errorf("expected the events shifted by 2 ticks, got %v", r.Events)
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/mevdschee/raylib-go-mines/clips"
+	"github.com/mevdschee/raylib-go-mines/clocks"
+	"github.com/mevdschee/raylib-go-mines/engine"
+	"github.com/mevdschee/raylib-go-mines/rawvf"
+	"github.com/mevdschee/raylib-go-mines/replays"
+	"github.com/mevdschee/raylib-go-mines/xdg"
+)
+
+// replayDir is the directory in the data dir where the replays are saved
+const replayDir = "replays"
+
+var playbackSpeeds = []float64{0.25, 0.5, 1, 2, 4, 8}
+
+// seekTicks is the distance of a seek
+const seekTicks = 5 * ticksPerSecond
+
+// playback drives the game with the events of a replay instead of the mouse
+type playback struct {
+	replay      *replays.Replay
+	clock       *clocks.Manual
+	next        int
+	paused      bool
+	speed       int
+	accumulated time.Duration
+	pointer     clips.Pointer
+	verified    bool
+	diverged    bool
+}
+
+// newReplay starts recording the game that was just restarted
+func (g *game) newReplay() {
+	g.ticks = 0
+	g.replay = &replays.Replay{
+		Version:        replays.Version,
+		TicksPerSecond: ticksPerSecond,
+		Width:          g.c.width,
+		Height:         g.c.height,
+		Bombs:          g.c.bombs,
+		Seed:           g.seed,
+		FirstClick:     g.c.firstClick,
+		NoGuess:        g.c.noGuess,
+		Questions:      g.c.questions,
+		Practice:       g.c.practice,
+		Holding:        g.c.holding,
+		Shared:         g.c.code != nil,
+		Events:         []replays.Event{},
+	}
+	if g.c.board != nil {
+		text, err := g.c.board.MarshalText()
+		if err == nil {
+			g.replay.Layout = string(text)
+		}
+	}
+}
+
+// record adds an action at the current tick to the replay
+func (g *game) record(action string) {
+	if g.replay != nil && g.playback == nil {
+		g.replay.Add(g.ticks, action, g.pointerX, g.pointerY)
+	}
+}
+
+// recordPointer records the buttons of the pointer and its moves while the left button is down
+func (g *game) recordPointer(p clips.Pointer) {
+	x, y := int(math.Floor(float64(p.X))), int(math.Floor(float64(p.Y)))
+	moved := x != g.pointerX || y != g.pointerY
+	g.pointerX, g.pointerY = x, y
+	if p.Pressed {
+		g.record(replays.ActionPress)
+		g.down = true
+	} else if g.down && moved {
+		g.record(replays.ActionMove)
+	}
+	if p.RightPressed {
+		g.record(replays.ActionRightPress)
+	}
+	if p.Released {
+		g.record(replays.ActionRelease)
+		g.down = false
+	}
+}
+
+// getMines lists the indices of the bombs on the board
+func (g *game) getMines() []int {
+	mines := []int{}
+	for y := 0; y < g.c.height; y++ {
+		for x := 0; x < g.c.width; x++ {
+			if g.tiles[y][x].bomb {
+				mines = append(mines, y*g.c.width+x)
+			}
+		}
+	}
+	return mines
+}
+
+// saveReplay saves the replay of a started game in the data dir
+func (g *game) saveReplay() {
+	if g.replay == nil || g.playback != nil || g.board == nil {
+		return
+	}
+	r := g.replay
+	g.replay = nil
+	if g.code != nil {
+		r.Code = g.code.String()
+	}
+	r.Mines = g.getMines()
+	r.Result = stateNames[g.state]
+	r.Closed = g.closed
+	r.Ticks = g.ticks
+	data, err := replays.Marshal(r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	dir, err := xdg.DataDir()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	name := time.Now().Format("20060102-150405")
+	if r.Code != "" {
+		name += "-" + r.Code
+	}
+	fileName := filepath.Join(dir, replayDir, name+".json")
+	if err := xdg.WriteFile(fileName, data); err != nil {
+		log.Println(err)
+	}
+}
+
+// loadReplay reads a replay file, or a RAW Vienna replay when it is not JSON
+func loadReplay(fileName string) (*replays.Replay, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(fileName), ".json") {
+		return replays.Unmarshal(data)
+	}
+	return rawvf.Read(data, ticksPerSecond)
+}
+
+// startPlayback restarts the game with the rules of the replay, so that its events can drive it
+func (g *game) startPlayback(r *replays.Replay, clock *clocks.Manual) error {
+	if r.TicksPerSecond != ticksPerSecond {
+		return fmt.Errorf("startPlayback: replay has %d ticks per second, expected %d", r.TicksPerSecond, ticksPerSecond)
+	}
+	c := g.c
+	c.width, c.height, c.bombs = r.Width, r.Height, r.Bombs
+	c.seed, c.code, c.board = r.Seed, nil, nil
+	c.firstClick, c.noGuess = r.FirstClick, r.NoGuess
+	c.questions, c.practice, c.holding = r.Questions, r.Practice, r.Holding
+	if r.Shared {
+		code, err := engine.ParseCode(r.Code)
+		if err != nil {
+			return err
+		}
+		c.code = &code
+	}
+	if r.Code == "" {
+		board, err := r.Board()
+		if err != nil {
+			return err
+		}
+		c.board = board
+	}
+	if g.movie != nil && (c.width != g.c.width || c.height != g.c.height) {
+		return fmt.Errorf("startPlayback: replay has a different board size")
+	}
+	g.c = c
+	p := &playback{replay: r, clock: clock, speed: 2}
+	if g.playback != nil {
+		p.paused, p.speed = g.playback.paused, g.playback.speed
+	}
+	g.playback = p
+	if g.movie != nil {
+		// presses of the previous run must not become long presses
+		for _, clip := range g.getClips("icons") {
+			clip.CancelPress()
+		}
+		g.getClips("button")[0].CancelPress()
+	}
+	g.restart()
+	return nil
+}
+
+// applyEvents drives the game with the events of the replay up to the current tick
+func (g *game) applyEvents() {
+	p := g.playback
+	for p.next < len(p.replay.Events) && p.replay.Events[p.next].Tick <= g.ticks {
+		e := p.replay.Events[p.next]
+		p.next++
+		switch e.Action {
+		case replays.ActionHint:
+			g.showHint()
+		case replays.ActionUndo:
+			if g.undo() {
+				g.hint = nil
+			}
+		case replays.ActionRedo:
+			g.redo()
+		default:
+			p.pointer = clips.Pointer{
+				X:            float32(e.X),
+				Y:            float32(e.Y),
+				Pressed:      e.Action == replays.ActionPress,
+				Released:     e.Action == replays.ActionRelease,
+				RightPressed: e.Action == replays.ActionRightPress,
+			}
+			g.update(p.pointer)
+		}
+	}
+	p.pointer = clips.Pointer{X: p.pointer.X, Y: p.pointer.Y}
+	if !p.verified && g.isPlaybackDone() && p.replay.Result != "" {
+		p.verified = true
+		// a lost game from a RAW Vienna file has no number of closed squares
+		p.diverged = stateNames[g.state] != p.replay.Result || (p.replay.Closed != 0 && g.closed != p.replay.Closed)
+		if g.code != nil && p.replay.Code != g.code.String() {
+			p.diverged = true
+		}
+		if !p.diverged {
+			if _, err := p.replay.Board(); err != nil {
+				p.diverged = true
+			}
+		}
+	}
+}
+
+// isPlaybackDone returns whether or not all events of the replay are played
+func (g *game) isPlaybackDone() bool {
+	p := g.playback
+	return p.next >= len(p.replay.Events) && g.ticks >= p.replay.Ticks
+}
+
+// stepPlayback advances the playback by one tick
+func (g *game) stepPlayback() {
+	g.applyEvents()
+	g.playback.clock.Advance(tick)
+	g.Tick()
+	g.applyEvents()
+}
+
+// updatePlayback advances the playback by the frame time at the playback speed
+func (g *game) updatePlayback(frameTime time.Duration) {
+	p := g.playback
+	if p.paused || g.isPlaybackDone() {
+		return
+	}
+	p.accumulated += time.Duration(float64(frameTime) * playbackSpeeds[p.speed])
+	for ; p.accumulated >= tick && !g.isPlaybackDone(); p.accumulated -= tick {
+		g.stepPlayback()
+	}
+}
+
+// nextEvent pauses the playback and advances it to the tick of the next event
+func (g *game) nextEvent() {
+	p := g.playback
+	p.paused = true
+	if p.next >= len(p.replay.Events) {
+		return
+	}
+	target := p.replay.Events[p.next].Tick
+	for g.ticks < target {
+		g.stepPlayback()
+	}
+}
+
+// seekPlayback plays the replay from the start until the target tick without sound
+func (g *game) seekPlayback(target int) {
+	p := g.playback
+	if target < 0 {
+		target = 0
+	}
+	if target > p.replay.Ticks {
+		target = p.replay.Ticks
+	}
+	g.audio.SetMuted(true)
+	if target < g.ticks {
+		if err := g.startPlayback(p.replay, p.clock); err != nil {
+			log.Println(err)
+		}
+	}
+	for g.ticks < target && !g.isPlaybackDone() {
+		g.stepPlayback()
+	}
+	g.audio.SetMuted(g.c.muted)
+}
+
+// controlPlayback handles the keys for pause, step, speed and seek
+func (g *game) controlPlayback() {
+	p := g.playback
+	if rl.IsKeyPressed(rl.KeySpace) {
+		p.paused = !p.paused
+	}
+	if rl.IsKeyPressed(rl.KeyN) {
+		g.nextEvent()
+	}
+	if rl.IsKeyPressed(rl.KeyUp) && p.speed < len(playbackSpeeds)-1 {
+		p.speed++
+	}
+	if rl.IsKeyPressed(rl.KeyDown) && p.speed > 0 {
+		p.speed--
+	}
+	if rl.IsKeyPressed(rl.KeyLeft) {
+		g.seekPlayback(g.ticks - seekTicks)
+	}
+	if rl.IsKeyPressed(rl.KeyRight) {
+		g.seekPlayback(g.ticks + seekTicks)
+	}
+}
+
+func (g *game) drawPlayback(scale int) {
+	p := g.playback
+	if p == nil {
+		return
+	}
+	s := int32(scale)
+	width, _ := g.getSize()
+	fontSize := 5 * s
+	seconds := func(ticks int) string {
+		return strconv.FormatFloat(float64(ticks)/ticksPerSecond, 'f', 1, 64)
+	}
+	text := "Replay " + strconv.FormatFloat(playbackSpeeds[p.speed], 'g', -1, 64) + "x " +
+		seconds(g.ticks) + "/" + seconds(p.replay.Ticks) + "s"
+	switch {
+	case p.diverged:
+		text += " - diverged"
+	case g.isPlaybackDone():
+		text += " - end"
+	case p.paused:
+		text += " - paused"
+	}
+	rl.DrawRectangle(0, 0, int32(width*scale), fontSize+2*s, rl.Fade(rl.Black, 0.7))
+	rl.DrawText(text, 2*s, s, fontSize, rl.White)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mevdschee/raylib-go-mines/engine"
+	"github.com/mevdschee/raylib-go-mines/replays"
+	"github.com/mevdschee/raylib-go-mines/xdg"
+)
+
+// randomConfig creates the config of a game on a random board with a seed
+func randomConfig(seed uint64) config {
+	return config{scale: 1, width: 9, height: 9, bombs: 10, seed: seed, firstClick: engine.FirstClickSafe}
+}
+
+// wait lets ticks pass without input
+func (tg *testGame) wait(ticks int) {
+	for i := 0; i < ticks; i++ {
+		tg.step()
+	}
+}
+
+// hold presses the left button on a tile for a number of ticks
+func (tg *testGame) hold(x, y, ticks int) {
+	p := getPointer(x, y)
+	p.Pressed = true
+	tg.input(p)
+	tg.wait(ticks)
+	p.Pressed, p.Released = false, true
+	tg.input(p)
+	tg.step()
+}
+
+// save saves the replay of the game and reads it back from the data dir
+func (tg *testGame) save() *replays.Replay {
+	tg.t.Helper()
+	tg.saveReplay()
+	dir, err := xdg.DataDir()
+	if err != nil {
+		tg.t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, replayDir, "*.json"))
+	if err != nil || len(files) != 1 {
+		tg.t.Fatalf("expected a replay file, got %v", files)
+	}
+	r, err := loadReplay(files[0])
+	if err != nil {
+		tg.t.Fatal(err)
+	}
+	return r
+}
+
+// play plays the replay in a new game until its end
+func playReplay(t *testing.T, r *replays.Replay) *testGame {
+	t.Helper()
+	g := newTestGame(t, randomConfig(1))
+	if err := g.startPlayback(r, g.manual); err != nil {
+		t.Fatal(err)
+	}
+	for steps := 0; !g.isPlaybackDone(); steps++ {
+		if steps > r.Ticks {
+			t.Fatalf("expected the playback to end at tick %d", r.Ticks)
+		}
+		g.stepPlayback()
+	}
+	return g
+}
+
+// assertSameGame checks that the played back game ended exactly like the recorded one
+func assertSameGame(t *testing.T, recorded, played *testGame, ticks int) {
+	t.Helper()
+	if played.playback.diverged {
+		t.Errorf("expected the playback not to diverge")
+	}
+	if played.ticks != ticks {
+		t.Errorf("expected %d ticks, got %d", ticks, played.ticks)
+	}
+	if played.state != recorded.state || played.closed != recorded.closed || played.bombs != recorded.bombs {
+		t.Errorf("expected state %d with %d closed, got state %d with %d closed", recorded.state, recorded.closed, played.state, played.closed)
+	}
+	if !reflect.DeepEqual(played.tiles, recorded.tiles) {
+		t.Errorf("expected the same tiles")
+	}
+	if played.clicks != recorded.clicks {
+		t.Errorf("expected clicks %+v, got %+v", recorded.clicks, played.clicks)
+	}
+	if played.stats == nil || recorded.stats == nil || *played.stats != *recorded.stats {
+		t.Errorf("expected the same stats")
+	}
+}
+
+func TestPlaybackIsBitExact(t *testing.T) {
+	g := newTestGame(t, randomConfig(42))
+	g.wait(20)
+	g.click(4, 4)
+	flagged, held := false, false
+	for y := 0; y < g.c.height; y++ {
+		for x := 0; x < g.c.width; x++ {
+			if g.tiles[y][x].open {
+				continue
+			}
+			g.wait(7)
+			if g.tiles[y][x].bomb {
+				// flag a bomb with the right button and one with a long press
+				if !flagged {
+					g.rightClick(x, y)
+					flagged = true
+				} else if !held {
+					g.hold(x, y, g.c.holding+5)
+					held = true
+				}
+				continue
+			}
+			g.click(x, y)
+		}
+	}
+	g.wait(5)
+	if g.state != stateWon || g.clicks.Right != 2 {
+		t.Fatalf("expected the recorded game to be won with 2 flags")
+	}
+	ticks := g.ticks
+	r := g.save()
+	if r.Result != "won" || r.Ticks != ticks {
+		t.Fatalf("expected a won replay of %d ticks, got %s in %d", ticks, r.Result, r.Ticks)
+	}
+	played := playReplay(t, r)
+	assertSameGame(t, g, played, ticks)
+}
+
+func TestPlaybackOfLostGame(t *testing.T) {
+	g := newTestGame(t, randomConfig(7))
+	g.click(0, 0)
+	for i := 0; g.state == statePlaying; i++ {
+		x, y := i%g.c.width, i/g.c.width
+		if g.tiles[y][x].bomb {
+			g.wait(3)
+			g.click(x, y)
+		}
+	}
+	if g.state != stateLost {
+		t.Fatalf("expected the recorded game to be lost")
+	}
+	ticks := g.ticks
+	played := playReplay(t, g.save())
+	assertSameGame(t, g, played, ticks)
+}
@@ -0,0 +1,160 @@
+package replays
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mevdschee/raylib-go-mines/engine"
+)
+
+// Version is the version of the replay file format, since version 2 the first
+// click of a shared game is an event
+const Version = 2
+
+// Actions of the events in a replay
+const (
+	ActionPress      = "press"
+	ActionRelease    = "release"
+	ActionRightPress = "rightPress"
+	ActionMove       = "move"
+	ActionHint       = "hint"
+	ActionUndo       = "undo"
+	ActionRedo       = "redo"
+)
+
+// Event is an action of the player at a tick of the game, with the pointer position in unscaled pixels
+type Event struct {
+	Tick   int    `json:"tick"`
+	Action string `json:"action"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+}
+
+// Replay is a recorded game: the rules, the board and the events that played it
+type Replay struct {
+	Version        int               `json:"version"`
+	TicksPerSecond int               `json:"ticksPerSecond"`
+	Width          int               `json:"width"`
+	Height         int               `json:"height"`
+	Bombs          int               `json:"bombs"`
+	Seed           uint64            `json:"seed,string"`
+	FirstClick     engine.FirstClick `json:"firstClick"`
+	NoGuess        bool              `json:"noGuess"`
+	Questions      bool              `json:"questions"`
+	Practice       bool              `json:"practice"`
+	Holding        int               `json:"holding"`
+	Shared         bool              `json:"shared"`
+	Code           string            `json:"code,omitempty"`
+	Layout         string            `json:"layout,omitempty"`
+	Mines          []int             `json:"mines"`
+	Result         string            `json:"result,omitempty"`
+	Closed         int               `json:"closed,omitempty"`
+	Ticks          int               `json:"ticks"`
+	Events         []Event           `json:"events"`
+}
+
+// Add appends an event to the replay
+func (r *Replay) Add(tick int, action string, x, y int) {
+	r.Events = append(r.Events, Event{Tick: tick, Action: action, X: x, Y: y})
+}
+
+// Board generates the board from the code and checks it against the recorded mines,
+// without a code the board is read from the text board or made from the mines
+func (r *Replay) Board() (*engine.Board, error) {
+	if r.Code == "" && r.Layout != "" {
+		board := &engine.Board{}
+		if err := board.UnmarshalText([]byte(r.Layout)); err != nil {
+			return nil, err
+		}
+		if board.Width != r.Width || board.Height != r.Height || (r.Mines != nil && !Matches(board, r.Mines)) {
+			return nil, fmt.Errorf("Board: board does not match the recorded mines")
+		}
+		return board, nil
+	}
+	if r.Code == "" {
+		board := engine.NewBoard(r.Width, r.Height)
+		for _, i := range r.Mines {
+			if i < 0 || i >= r.Width*r.Height {
+				return nil, fmt.Errorf("Board: invalid mine %d", i)
+			}
+			board.SetBomb(i%r.Width, i/r.Width, true)
+		}
+		if board.Bombs() != r.Bombs {
+			return nil, fmt.Errorf("Board: expected %d mines, got %d", r.Bombs, board.Bombs())
+		}
+		return board, nil
+	}
+	code, err := engine.ParseCode(r.Code)
+	if err != nil {
+		return nil, err
+	}
+	if code.Width != r.Width || code.Height != r.Height || code.Bombs != r.Bombs {
+		return nil, fmt.Errorf("Board: code '%s' does not match the replay size", r.Code)
+	}
+	board := code.Generate()
+	if !Matches(board, r.Mines) {
+		return nil, fmt.Errorf("Board: code '%s' does not match the recorded mines", r.Code)
+	}
+	return board, nil
+}
+
+// Mines lists the indices of the mines on the board
+func Mines(board *engine.Board) []int {
+	mines := []int{}
+	for i := 0; i < board.Width*board.Height; i++ {
+		if board.IsBomb(i%board.Width, i/board.Width) {
+			mines = append(mines, i)
+		}
+	}
+	return mines
+}
+
+// Matches returns whether or not the mines are exactly the mines of the board
+func Matches(board *engine.Board, mines []int) bool {
+	actual := Mines(board)
+	if len(actual) != len(mines) {
+		return false
+	}
+	for i := range actual {
+		if actual[i] != mines[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Marshal encodes the replay as JSON
+func Marshal(r *Replay) ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Unmarshal decodes a replay from JSON and checks its version
+func Unmarshal(data []byte) (*Replay, error) {
+	r := Replay{}
+	err := json.Unmarshal(data, &r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Version < 1 || r.Version > Version {
+		return nil, fmt.Errorf("Unmarshal: unsupported replay version %d", r.Version)
+	}
+	if r.Width <= 0 || r.Height <= 0 || r.Bombs < 0 || r.TicksPerSecond <= 0 {
+		return nil, fmt.Errorf("Unmarshal: invalid replay")
+	}
+	for i, e := range r.Events {
+		if e.Tick < 0 || (i > 0 && e.Tick < r.Events[i-1].Tick) {
+			return nil, fmt.Errorf("Unmarshal: events out of order at %d", i)
+		}
+	}
+	return &r, nil
+}
+
+// Load reads a replay file
+func Load(fileName string) (*Replay, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return Unmarshal(data)
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/mevdschee/raylib-go-mines/engine"
+	"github.com/mevdschee/raylib-go-mines/replays"
+	"github.com/mevdschee/raylib-go-mines/xdg"
+)
+
+// savedGameVersion is the version of the format of the saved game
+const savedGameVersion = 1
+
+// autoSaveTicks is the time between saves of a game in progress
+const autoSaveTicks = 30 * ticksPerSecond
+
+type statusJSON struct {
+	State  int `json:"state"`
+	Button int `json:"button"`
+	Bombs  int `json:"bombs"`
+	Closed int `json:"closed"`
+}
+
+type changeJSON struct {
+	Index  int      `json:"index"`
+	Before tileJSON `json:"before"`
+	After  tileJSON `json:"after"`
+}
+
+type moveJSON struct {
+	Kind    int          `json:"kind"`
+	X       int          `json:"x"`
+	Y       int          `json:"y"`
+	Changes []changeJSON `json:"changes"`
+	Before  statusJSON   `json:"before"`
+	After   statusJSON   `json:"after"`
+}
+
+// savedGame is a game in progress that can be resumed after a restart of the program
+type savedGame struct {
+	Version    int               `json:"version"`
+	Width      int               `json:"width"`
+	Height     int               `json:"height"`
+	Bombs      int               `json:"bombs"`
+	ConfigSeed uint64            `json:"configSeed,string"`
+	Shared     string            `json:"shared,omitempty"`
+	Custom     bool              `json:"custom,omitempty"`
+	Seed       uint64            `json:"seed,string"`
+	Code       string            `json:"code,omitempty"`
+	NoGuess    bool              `json:"noGuess"`
+	FirstClick engine.FirstClick `json:"firstClick"`
+	Questions  bool              `json:"questions"`
+	Practice   bool              `json:"practice"`
+	Elapsed    int64             `json:"elapsed"`
+	Status     statusJSON        `json:"status"`
+	Clicks     engine.Clicks     `json:"clicks"`
+	Hints      int               `json:"hints"`
+	Undos      int               `json:"undos"`
+	Assisted   bool              `json:"assisted"`
+	Tiles      [][]tileJSON      `json:"tiles"`
+	History    []moveJSON        `json:"history"`
+	Future     []moveJSON        `json:"future"`
+	Ticks      int               `json:"ticks"`
+	Replay     *replays.Replay   `json:"replay,omitempty"`
+	Daily      string            `json:"daily,omitempty"`
+	Ranked     bool              `json:"ranked,omitempty"`
+}
+
+// getSavedGameFile gets the file of the saved game, next to the settings
+func getSavedGameFile() (string, error) {
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "game.json"), nil
+}
+
+func toTileJSON(t tile) tileJSON {
+	return tileJSON{Open: t.open, Marked: t.marked, Question: t.question, Bomb: t.bomb, Number: t.number}
+}
+
+func fromTileJSON(t tileJSON) tile {
+	return tile{open: t.Open, marked: t.Marked, question: t.Question, bomb: t.Bomb, number: t.Number}
+}
+
+func toStatusJSON(s status) statusJSON {
+	return statusJSON{State: s.state, Button: s.button, Bombs: s.bombs, Closed: s.closed}
+}
+
+func fromStatusJSON(s statusJSON) status {
+	return status{state: s.State, button: s.Button, bombs: s.Bombs, closed: s.Closed}
+}
+
+func toMovesJSON(moves []*move) []moveJSON {
+	result := []moveJSON{}
+	for _, m := range moves {
+		mj := moveJSON{
+			Kind:    m.kind,
+			X:       m.x,
+			Y:       m.y,
+			Changes: []changeJSON{},
+			Before:  toStatusJSON(m.before),
+			After:   toStatusJSON(m.after),
+		}
+		for _, c := range m.changes {
+			mj.Changes = append(mj.Changes, changeJSON{Index: c.index, Before: toTileJSON(c.before), After: toTileJSON(c.after)})
+		}
+		result = append(result, mj)
+	}
+	return result
+}
+
+func fromMovesJSON(moves []moveJSON) []*move {
+	result := []*move{}
+	for _, mj := range moves {
+		m := &move{kind: mj.Kind, x: mj.X, y: mj.Y, before: fromStatusJSON(mj.Before), after: fromStatusJSON(mj.After)}
+		for _, c := range mj.Changes {
+			m.changes = append(m.changes, change{index: c.Index, before: fromTileJSON(c.Before), after: fromTileJSON(c.After)})
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+// isInProgress returns whether or not a game in the state can be saved and
+// resumed, a mine hit in practice mode can still be undone
+func isInProgress(state int) bool {
+	return state == statePlaying || state == stateHit
+}
+
+// getSavedGame gets the game in progress to save it
+func (g *game) getSavedGame() savedGame {
+	s := savedGame{
+		Version:    savedGameVersion,
+		Width:      g.c.width,
+		Height:     g.c.height,
+		Bombs:      g.c.bombs,
+		ConfigSeed: g.c.seed,
+		Custom:     g.c.board != nil,
+		Seed:       g.seed,
+		NoGuess:    g.c.noGuess,
+		FirstClick: g.c.firstClick,
+		Questions:  g.c.questions,
+		Practice:   g.c.practice,
+		Elapsed:    (g.clock.Now().UnixNano() - g.time) / 1000000,
+		Status:     toStatusJSON(g.getStatus()),
+		Clicks:     g.clicks,
+		Hints:      g.hints,
+		Undos:      g.undos,
+		Assisted:   g.assisted,
+		Tiles:      g.getBoard().Tiles,
+		History:    toMovesJSON(g.history),
+		Future:     toMovesJSON(g.future),
+		Ticks:      g.ticks,
+		Replay:     g.replay,
+		Daily:      g.c.daily,
+		Ranked:     g.dailyRanked,
+	}
+	if g.state == stateHit {
+		// the clock is stopped at the mine hit
+		s.Elapsed = (g.hitAt - g.time) / 1000000
+	}
+	if g.c.code != nil {
+		s.Shared = g.c.code.String()
+	}
+	if g.code != nil {
+		s.Code = g.code.String()
+	}
+	return s
+}
+
+// saveGame saves the game when it is in progress, or else removes the saved game
+func (g *game) saveGame() {
+	if g.playback != nil {
+		return
+	}
+	fileName, err := getSavedGameFile()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	g.savedAt = g.ticks
+	if !isInProgress(g.state) || g.board == nil {
+		if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+			log.Println(err)
+		}
+		return
+	}
+	data, err := json.Marshal(g.getSavedGame())
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if err := xdg.WriteFile(fileName, data); err != nil {
+		log.Println(err)
+	}
+}
+
+// autoSave saves the game in progress every once in a while
+func (g *game) autoSave() {
+	if isInProgress(g.state) && g.ticks-g.savedAt >= autoSaveTicks {
+		g.saveGame()
+	}
+}
+
+// loadSavedGame reads the saved game, it returns nil when there is none
+func loadSavedGame() (*savedGame, error) {
+	fileName, err := getSavedGameFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s := savedGame{}
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, err
+	}
+	if s.Version != savedGameVersion {
+		return nil, fmt.Errorf("loadSavedGame: unsupported version %d", s.Version)
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// validate checks that the saved game is in progress and that its tiles and
+// moves fit the board, so that undo and redo can not index outside of it
+func (s *savedGame) validate() error {
+	if s.Width <= 0 || s.Height <= 0 || len(s.Tiles) != s.Height || !isInProgress(s.Status.State) {
+		return fmt.Errorf("loadSavedGame: invalid saved game")
+	}
+	for _, row := range s.Tiles {
+		if len(row) != s.Width {
+			return fmt.Errorf("loadSavedGame: invalid saved game")
+		}
+	}
+	for _, moves := range [][]moveJSON{s.History, s.Future} {
+		for _, m := range moves {
+			if m.X < 0 || m.X >= s.Width || m.Y < 0 || m.Y >= s.Height {
+				return fmt.Errorf("loadSavedGame: invalid move at %d,%d", m.X, m.Y)
+			}
+			for _, c := range m.Changes {
+				if c.Index < 0 || c.Index >= s.Width*s.Height {
+					return fmt.Errorf("loadSavedGame: invalid change of tile %d", c.Index)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resume continues a saved game, the time the program was closed does not count
+func (g *game) resume(s *savedGame) error {
+	c := g.c
+	c.width, c.height, c.bombs = s.Width, s.Height, s.Bombs
+	c.seed, c.code, c.board = s.ConfigSeed, nil, nil
+	c.noGuess, c.firstClick = s.NoGuess, s.FirstClick
+	c.questions, c.practice = s.Questions, s.Practice
+	c.daily = s.Daily
+	if s.Shared != "" {
+		shared, err := engine.ParseCode(s.Shared)
+		if err != nil {
+			return err
+		}
+		c.code = &shared
+	}
+	var code *engine.Code
+	if s.Code != "" {
+		parsed, err := engine.ParseCode(s.Code)
+		if err != nil {
+			return err
+		}
+		code = &parsed
+	}
+	// the first click of a shared game is already in the saved tiles
+	g.c = c
+	g.c.code = nil
+	g.restart()
+	g.c.code = c.code
+	board := engine.NewBoard(s.Width, s.Height)
+	for y := 0; y < s.Height; y++ {
+		for x := 0; x < s.Width; x++ {
+			g.tiles[y][x] = fromTileJSON(s.Tiles[y][x])
+			board.SetBomb(x, y, g.tiles[y][x].bomb)
+		}
+	}
+	if s.Custom {
+		g.c.board = board
+	}
+	g.board = board
+	g.code = code
+	g.seed = s.Seed
+	g.setStatus(fromStatusJSON(s.Status))
+	g.time = g.clock.Now().UnixNano() - s.Elapsed*1000000
+	g.clicks = s.Clicks
+	g.hints, g.undos, g.assisted = s.Hints, s.Undos, s.Assisted
+	g.history = fromMovesJSON(s.History)
+	g.future = fromMovesJSON(s.Future)
+	if g.state == stateHit {
+		g.hitAt = g.clock.Now().UnixNano()
+	}
+	g.ticks, g.savedAt = s.Ticks, s.Ticks
+	g.dailyRanked = s.Ranked
+	if s.Replay != nil {
+		g.replay = s.Replay
+	}
+	return nil
+}
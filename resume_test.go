@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mevdschee/raylib-go-mines/xdg"
+)
+
+// saveAndLoad saves the game and reads it back from the config dir
+func (tg *testGame) saveAndLoad() *savedGame {
+	tg.t.Helper()
+	tg.saveGame()
+	s, err := loadSavedGame()
+	if err != nil {
+		tg.t.Fatal(err)
+	}
+	if s == nil {
+		tg.t.Fatalf("expected a saved game")
+	}
+	return s
+}
+
+// resumeTestGame resumes the saved game in a new game after the program was
+// closed for an hour
+func resumeTestGame(t *testing.T, s *savedGame) *testGame {
+	t.Helper()
+	g := newTestGame(t, randomConfig(1))
+	g.manual.Advance(time.Hour)
+	if err := g.resume(s); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestResume(t *testing.T) {
+	// a 3x3 board with a bomb in the bottom right corner
+	g := newTestGame(t, boardConfig(newTestBoard(3, 3, 2, 2)))
+	g.click(1, 1)
+	g.rightClick(2, 2)
+	if !g.undo() || g.state != statePlaying {
+		t.Fatalf("expected the game to be in progress")
+	}
+	g.wait(10)
+	// the saved game has the time in milliseconds
+	elapsed := (g.clock.Now().UnixNano() - g.time) / 1000000
+	s := g.saveAndLoad()
+	resumed := resumeTestGame(t, s)
+	if !reflect.DeepEqual(resumed.tiles, g.tiles) || resumed.getStatus() != g.getStatus() {
+		t.Fatalf("expected the same tiles and status")
+	}
+	if resumed.clicks != g.clicks || resumed.ticks != g.ticks || len(resumed.history) != 1 || len(resumed.future) != 1 {
+		t.Fatalf("expected the same clicks, ticks and moves")
+	}
+	// the hour that the program was closed does not count
+	if got := (resumed.clock.Now().UnixNano() - resumed.time) / 1000000; got != elapsed {
+		t.Errorf("expected %d ms elapsed, got %d ms", elapsed, got)
+	}
+	if !resumed.redo() || !resumed.tiles[2][2].marked {
+		t.Fatalf("expected the undone flag to be redone")
+	}
+	resumed.click(0, 0)
+	if resumed.state != stateWon {
+		t.Fatalf("expected the game to be won, got state %d", resumed.state)
+	}
+	// the saved game is removed when the game is over
+	resumed.saveGame()
+	if s, err := loadSavedGame(); s != nil || err != nil {
+		t.Errorf("expected no saved game, got %v", err)
+	}
+}
+
+func TestResumePracticeMineHit(t *testing.T) {
+	// a 3x3 board with bombs in the top right and bottom right corner
+	g := newTestGame(t, practiceConfig(newTestBoard(3, 3, 2, 0, 2, 2)))
+	g.click(0, 0)
+	g.click(2, 2)
+	if g.state != stateHit {
+		t.Fatalf("expected a paused game, got state %d", g.state)
+	}
+	g.wait(10)
+	s := g.saveAndLoad()
+	resumed := resumeTestGame(t, s)
+	if resumed.state != stateHit {
+		t.Fatalf("expected the resumed game to be paused, got state %d", resumed.state)
+	}
+	resumed.wait(10)
+	if !resumed.undo() || resumed.state != statePlaying {
+		t.Fatalf("expected the mine hit to be undone")
+	}
+	// only the time until the mine hit counts
+	if elapsed := (resumed.clock.Now().UnixNano() - resumed.time) / 1000000; elapsed != int64(2*tick/time.Millisecond) {
+		t.Errorf("expected %d ms elapsed, got %d ms", 2*tick/time.Millisecond, elapsed)
+	}
+}
+
+func TestLoadSavedGameRejectsInvalidMoves(t *testing.T) {
+	g := newTestGame(t, boardConfig(newTestBoard(3, 3, 2, 2)))
+	g.click(1, 1)
+	g.rightClick(2, 2)
+	valid := g.getSavedGame()
+	tests := []struct {
+		name   string
+		change func(s *savedGame)
+	}{
+		{"change after the last tile", func(s *savedGame) { s.History[0].Changes[0].Index = 9 }},
+		{"negative change", func(s *savedGame) { s.History[1].Changes[0].Index = -1 }},
+		{"move outside", func(s *savedGame) { s.History[0].X = 3 }},
+		{"negative move", func(s *savedGame) { s.History[1].Y = -1 }},
+		{"redo outside", func(s *savedGame) {
+			s.Future = append(s.Future, moveJSON{X: 1, Y: 1, Changes: []changeJSON{{Index: 100}}})
+		}},
+		{"game over", func(s *savedGame) { s.Status.State = stateLost }},
+		{"missing row", func(s *savedGame) { s.Tiles = s.Tiles[:2] }},
+	}
+	fileName, err := getSavedGameFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		s := valid
+		// deep copy through the file format
+		data, _ := json.Marshal(valid)
+		json.Unmarshal(data, &s)
+		test.change(&s)
+		data, _ = json.Marshal(s)
+		if err := xdg.WriteFile(fileName, data); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadSavedGame(); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+	os.Remove(fileName)
+	if s, err := loadSavedGame(); s != nil || err != nil {
+		t.Errorf("expected no saved game, got %v", err)
+	}
+}
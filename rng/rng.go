@@ -0,0 +1,46 @@
+package rng
+
+// Source is a deterministic random number generator (SplitMix64) that
+// produces the same sequence on every platform and Go version
+type Source struct {
+	state uint64
+}
+
+// New creates a new source from a seed
+func New(seed uint64) *Source {
+	return &Source{state: seed}
+}
+
+// Uint64 returns the next pseudo-random 64 bit value
+func (s *Source) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Intn returns a value in [0,n) without modulo bias, it panics if n <= 0
+func (s *Source) Intn(n int) int {
+	if n <= 0 {
+		panic("rng: invalid argument to Intn")
+	}
+	max := uint64(n)
+	limit := -max % max
+	for {
+		v := s.Uint64()
+		if v >= limit {
+			return int(v % max)
+		}
+	}
+}
+
+// Float64 returns a value in [0,1)
+func (s *Source) Float64() float64 {
+	return float64(s.Uint64()>>11) / (1 << 53)
+}
+
+// Range returns a value in [min,max)
+func (s *Source) Range(min, max float64) float64 {
+	return min + (max-min)*s.Float64()
+}
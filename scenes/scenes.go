@@ -17,8 +17,8 @@ type Scene struct {
 
 // SceneJSON is a set of layers in JSON
 type SceneJSON struct {
-	Name   string
-	Layers []layers.LayerJSON
+	Name   string             `json:"name" schema:"required"`
+	Layers []layers.LayerJSON `json:"layers"`
 }
 
 // GetName gets the name of the scene
@@ -31,6 +31,11 @@ func (s *Scene) GetLayers() map[string]*layers.Layer {
 	return s.layers
 }
 
+// GetOrder gets the names of the layers in drawing order
+func (s *Scene) GetOrder() []string {
+	return s.order
+}
+
 // New creates a new scene
 func New(name string) *Scene {
 	return &Scene{
@@ -71,10 +76,10 @@ func (s *Scene) Draw(scale int) {
 	}
 }
 
-// Update updates the scene
-func (s *Scene) Update(scale int) (err error) {
+// Update updates the scene with the pointer
+func (s *Scene) Update(p clips.Pointer) (err error) {
 	for _, name := range s.order {
-		err = s.layers[name].Update(scale)
+		err = s.layers[name].Update(p)
 		if err != nil {
 			break
 		}
@@ -82,6 +87,13 @@ func (s *Scene) Update(scale int) (err error) {
 	return err
 }
 
+// Tick advances the layers of the scene in time
+func (s *Scene) Tick() {
+	for _, name := range s.order {
+		s.layers[name].Tick()
+	}
+}
+
 // GetClip gets a clip from the scene
 func (s *Scene) GetClip(layer, clip string, i int) (*clips.Clip, error) {
 	if l, ok := s.layers[layer]; ok {
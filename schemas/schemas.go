@@ -0,0 +1,217 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect of the generated documents
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document
+type Schema map[string]interface{}
+
+const expressionDescription = "expression evaluated with expr, may use the parameters (e.g. 'w', 'h') and the repeat index 'i'"
+
+const handlerDescription = "expression evaluated with expr when the event happens, may emit events with emit('name') and call actions with call('name', args...)"
+
+// New creates a schema document for an array of values of the given type
+func New(id, title string, item interface{}) Schema {
+	schema := Schema{
+		"$schema": Draft,
+		"$id":     id,
+		"title":   title,
+		"type":    "array",
+		"items":   FromType(reflect.TypeOf(item)),
+	}
+	return schema
+}
+
+// FromType creates a schema from a Go type using its json and schema tags
+func FromType(t reflect.Type) Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return FromType(t.Elem())
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Array:
+		return Schema{
+			"type":     "array",
+			"items":    FromType(t.Elem()),
+			"minItems": t.Len(),
+			"maxItems": t.Len(),
+		}
+	case reflect.Slice:
+		return Schema{"type": "array", "items": FromType(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": FromType(t.Elem())}
+	case reflect.Struct:
+		return fromStruct(t)
+	}
+	return Schema{}
+}
+
+func fromStruct(t reflect.Type) Schema {
+	properties := Schema{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, ok := fieldName(field)
+		if !ok {
+			continue
+		}
+		property := FromType(field.Type)
+		for _, option := range strings.Split(field.Tag.Get("schema"), ",") {
+			switch option {
+			case "expression":
+				property["description"] = expressionDescription
+				property["minLength"] = 1
+			case "handler":
+				property["description"] = handlerDescription
+				property["minLength"] = 1
+			case "required":
+				required = append(required, name)
+			}
+		}
+		properties[name] = property
+	}
+	schema := Schema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func fieldName(field reflect.StructField) (string, bool) {
+	tag := strings.Split(field.Tag.Get("json"), ",")[0]
+	if tag == "-" {
+		return "", false
+	}
+	if tag != "" {
+		return tag, true
+	}
+	return strings.ToLower(field.Name[:1]) + field.Name[1:], true
+}
+
+// Validate checks a JSON document against a schema, it supports the keywords
+// that are generated by FromType
+func Validate(schema Schema, data []byte) error {
+	// the schema is normalized to the types of decoded JSON
+	normalized, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	var s interface{}
+	err = json.Unmarshal(normalized, &s)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+	return validate(s, value, "")
+}
+
+func validate(schema, value interface{}, path string) error {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		if schema == false {
+			return fmt.Errorf("Validate: %s is not allowed", getPath(path))
+		}
+		return nil
+	}
+	if t, ok := s["type"].(string); ok && !hasType(value, t) {
+		return fmt.Errorf("Validate: %s must be of type %s", getPath(path), t)
+	}
+	switch v := value.(type) {
+	case string:
+		if min, ok := s["minLength"].(float64); ok && float64(len(v)) < min {
+			return fmt.Errorf("Validate: %s must have a length of at least %v", getPath(path), min)
+		}
+	case []interface{}:
+		if min, ok := s["minItems"].(float64); ok && float64(len(v)) < min {
+			return fmt.Errorf("Validate: %s must have at least %v items", getPath(path), min)
+		}
+		if max, ok := s["maxItems"].(float64); ok && float64(len(v)) > max {
+			return fmt.Errorf("Validate: %s must have at most %v items", getPath(path), max)
+		}
+		for i, item := range v {
+			err := validate(s["items"], item, path+"/"+strconv.Itoa(i))
+			if err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		required, _ := s["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := v[name.(string)]; !ok {
+				return fmt.Errorf("Validate: %s is missing '%s'", getPath(path), name)
+			}
+		}
+		properties, _ := s["properties"].(map[string]interface{})
+		names := []string{}
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := properties[name]
+			if !ok {
+				property, ok = s["additionalProperties"]
+			}
+			if !ok {
+				continue
+			}
+			err := validate(property, v[name], path+"/"+name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func hasType(value interface{}, t string) bool {
+	switch v := value.(type) {
+	case bool:
+		return t == "boolean"
+	case float64:
+		return t == "number" || (t == "integer" && v == math.Trunc(v))
+	case string:
+		return t == "string"
+	case []interface{}:
+		return t == "array"
+	case map[string]interface{}:
+		return t == "object"
+	}
+	return false
+}
+
+// getPath gets the JSON pointer of a value for an error message
+func getPath(path string) string {
+	if path == "" {
+		return "document"
+	}
+	return "'" + path + "'"
+}
@@ -0,0 +1,85 @@
+package schemas
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testClip struct {
+	Name    string  `json:"name" schema:"required"`
+	X       string  `json:"x" schema:"expression"`
+	Size    [2]int  `json:"size,omitempty"`
+	Frames  []int   `json:"frames,omitempty"`
+	Visible bool    `json:"visible"`
+	Scale   float64 `json:"scale"`
+	Hidden  string  `json:"-"`
+	private int
+	Tags    []string `json:"tags,omitempty"`
+}
+
+func TestFromType(t *testing.T) {
+	schema := FromType(reflect.TypeOf(testClip{}))
+	properties := schema["properties"].(Schema)
+	if len(properties) != 7 {
+		t.Errorf("expected 7 properties, got %d", len(properties))
+	}
+	if _, ok := properties["Hidden"]; ok {
+		t.Errorf("expected field with json tag '-' to be skipped")
+	}
+	if got := properties["x"].(Schema)["description"]; got != expressionDescription {
+		t.Errorf("expected expression description, got %v", got)
+	}
+	if got := properties["size"].(Schema)["maxItems"]; got != 2 {
+		t.Errorf("expected maxItems 2, got %v", got)
+	}
+	if got := schema["required"]; !reflect.DeepEqual(got, []string{"name"}) {
+		t.Errorf("expected required [name], got %v", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema := New("test.schema.json", "Test", testClip{})
+	tests := []struct {
+		document string
+		err      string
+	}{
+		{`[]`, ""},
+		{`[{"name":"a","x":"i*16","size":[1,2],"frames":[1],"visible":true,"scale":1.5}]`, ""},
+		{`{}`, "document must be of type array"},
+		{`[{"x":"1"}]`, "'/0' is missing 'name'"},
+		{`[{"name":"a","y":"1"}]`, "'/0/y' is not allowed"},
+		{`[{"name":"a","x":""}]`, "'/0/x' must have a length of at least 1"},
+		{`[{"name":"a","size":[1]}]`, "'/0/size' must have at least 2 items"},
+		{`[{"name":"a","size":[1,2,3]}]`, "'/0/size' must have at most 2 items"},
+		{`[{"name":"a","frames":[1.5]}]`, "'/0/frames/0' must be of type integer"},
+		{`[{"name":"a","scale":"1"}]`, "'/0/scale' must be of type number"},
+		{`[{"name":"a","visible":1}]`, "'/0/visible' must be of type boolean"},
+		{`[`, "unexpected end of JSON input"},
+	}
+	for _, test := range tests {
+		err := Validate(schema, []byte(test.document))
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", test.document, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: expected error '%s', got %v", test.document, test.err, err)
+		}
+	}
+}
+
+func TestValidateDecodedSchema(t *testing.T) {
+	data, err := json.Marshal(New("test.schema.json", "Test", testClip{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := Schema{}
+	err = json.Unmarshal(data, &schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(schema, []byte(`[{"x":"1"}]`)); err == nil {
+		t.Errorf("expected missing name to be an error")
+	}
+}
@@ -0,0 +1,152 @@
+package scores
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mevdschee/raylib-go-mines/engine"
+	"github.com/mevdschee/raylib-go-mines/xdg"
+)
+
+// Version is the version of the high-score file format
+const Version = 1
+
+// MaxEntries is the number of entries kept per table
+const MaxEntries = 10
+
+// Key identifies a table of high scores: the board size and the rules
+type Key struct {
+	Width      int
+	Height     int
+	Bombs      int
+	NoGuess    bool
+	FirstClick engine.FirstClick
+}
+
+// String gets the name of the table, e.g. "9x9x10" or "30x16x99-noguess-xpcorner"
+func (k Key) String() string {
+	s := strconv.Itoa(k.Width) + "x" + strconv.Itoa(k.Height) + "x" + strconv.Itoa(k.Bombs)
+	if k.NoGuess {
+		s += "-noguess"
+	}
+	if k.FirstClick != engine.FirstClickSafe {
+		s += "-" + strings.ToLower(strings.ReplaceAll(k.FirstClick.String(), " ", ""))
+	}
+	return s
+}
+
+// Entry is a won game in a table
+type Entry struct {
+	Name          string  `json:"name"`
+	Milliseconds  int64   `json:"milliseconds"`
+	BBBVPerSecond float64 `json:"3bvPerSecond"`
+	Date          string  `json:"date"`
+}
+
+// IsValid returns whether or not the entry has a name and a time
+func (e Entry) IsValid() bool {
+	return e.Milliseconds > 0 && strings.TrimSpace(e.Name) != ""
+}
+
+// Store holds the tables of high scores of a file
+type Store struct {
+	fileName string
+	Version  int                `json:"version"`
+	Tables   map[string][]Entry `json:"tables"`
+}
+
+// DefaultFileName gets the file of the high scores in the data directory
+func DefaultFileName() (string, error) {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scores.json"), nil
+}
+
+// New creates an empty store for a file
+func New(fileName string) *Store {
+	return &Store{fileName: fileName, Version: Version, Tables: map[string][]Entry{}}
+}
+
+// Load reads the store from the file, a missing file is an empty store, a
+// corrupted file is moved aside and returns an empty store with the error
+func Load(fileName string) (*Store, error) {
+	s := New(fileName)
+	data, err := os.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	loaded := New(fileName)
+	err = json.Unmarshal(data, loaded)
+	if err == nil && (loaded.Version < 1 || loaded.Version > Version) {
+		err = fmt.Errorf("Load: unsupported high-score version %d", loaded.Version)
+	}
+	if err != nil {
+		os.Rename(fileName, fileName+".corrupt")
+		return s, err
+	}
+	for key, entries := range loaded.Tables {
+		for _, entry := range entries {
+			// skip invalid entries instead of rejecting the file
+			if entry.IsValid() {
+				s.Tables[key] = append(s.Tables[key], entry)
+			}
+		}
+		s.sort(key)
+	}
+	return s, nil
+}
+
+func (s *Store) sort(key string) {
+	entries := s.Tables[key]
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Milliseconds != entries[j].Milliseconds {
+			return entries[i].Milliseconds < entries[j].Milliseconds
+		}
+		return entries[i].BBBVPerSecond > entries[j].BBBVPerSecond
+	})
+	if len(entries) > MaxEntries {
+		entries = entries[:MaxEntries]
+	}
+	s.Tables[key] = entries
+}
+
+// Get gets the entries of a table from best to worst
+func (s *Store) Get(key Key) []Entry {
+	return s.Tables[key.String()]
+}
+
+// Add adds an entry to a table and returns its rank (from 0), or -1 when it is
+// not a high score or not valid (it would not be loaded)
+func (s *Store) Add(key Key, entry Entry) int {
+	if !entry.IsValid() {
+		return -1
+	}
+	name := key.String()
+	s.Tables[name] = append(s.Tables[name], entry)
+	s.sort(name)
+	for i, e := range s.Tables[name] {
+		if e == entry {
+			return i
+		}
+	}
+	return -1
+}
+
+// Save writes the store to its file atomically
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return xdg.WriteFile(s.fileName, data)
+}
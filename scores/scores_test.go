@@ -0,0 +1,82 @@
+package scores
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var beginner = Key{Width: 9, Height: 9, Bombs: 10}
+
+func TestSaveAndLoad(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "scores.json")
+	s := New(fileName)
+	entries := []Entry{
+		{Name: "alice", Milliseconds: 12000, BBBVPerSecond: 1.5, Date: "2026-10-19"},
+		{Name: "bob", Milliseconds: 9000, BBBVPerSecond: 2.25, Date: "2026-10-18"},
+	}
+	if rank := s.Add(beginner, entries[0]); rank != 0 {
+		t.Errorf("expected rank 0, got %d", rank)
+	}
+	if rank := s.Add(beginner, entries[1]); rank != 0 {
+		t.Errorf("expected rank 0, got %d", rank)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Entry{entries[1], entries[0]}
+	if !reflect.DeepEqual(loaded.Get(beginner), expected) {
+		t.Errorf("expected %v, got %v", expected, loaded.Get(beginner))
+	}
+}
+
+func TestAddRejectsWhatLoadSkips(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "scores.json"))
+	for _, entry := range []Entry{
+		{Name: "", Milliseconds: 1000},
+		{Name: "  ", Milliseconds: 1000},
+		{Name: "carol", Milliseconds: 0},
+	} {
+		if rank := s.Add(beginner, entry); rank != -1 {
+			t.Errorf("expected %+v to be rejected, got rank %d", entry, rank)
+		}
+	}
+	if len(s.Get(beginner)) != 0 {
+		t.Errorf("expected no entries, got %v", s.Get(beginner))
+	}
+}
+
+func TestLoadSkipsInvalidEntries(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "scores.json")
+	data := `{"version":1,"tables":{"9x9x10":[{"name":"","milliseconds":1000},{"name":"dave","milliseconds":2000}]}}`
+	if err := os.WriteFile(fileName, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := s.Get(beginner)
+	if len(entries) != 1 || entries[0].Name != "dave" {
+		t.Errorf("expected only the entry of dave, got %v", entries)
+	}
+}
+
+func TestLoadMovesCorruptFileAside(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "scores.json")
+	if err := os.WriteFile(fileName, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(fileName)
+	if err == nil || s == nil || len(s.Tables) != 0 {
+		t.Fatalf("expected an empty store and an error")
+	}
+	if _, err := os.Stat(fileName + ".corrupt"); err != nil {
+		t.Errorf("expected the file to be moved aside: %v", err)
+	}
+}
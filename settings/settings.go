@@ -0,0 +1,184 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mevdschee/raylib-go-mines/engine"
+	"github.com/mevdschee/raylib-go-mines/xdg"
+)
+
+// Version is the version of the settings file format
+const Version = 2
+
+// MaxPresets is the number of custom presets that is kept
+const MaxPresets = 3
+
+// migrations upgrade the raw settings of a version to the next version, the
+// migration of version n is at index n-1, fields that are missing after the
+// migrations get their default value
+var migrations = []func(raw map[string]interface{}){
+	// version 1 could have more bombs than fit on the board, they are reduced
+	// to the most that fit instead of resetting the board size
+	func(raw map[string]interface{}) {
+		width, _ := raw["width"].(float64)
+		height, _ := raw["height"].(float64)
+		bombs, _ := raw["bombs"].(float64)
+		if width > 0 && height > 0 && bombs >= width*height {
+			raw["bombs"] = width*height - 1
+		}
+	},
+}
+
+// Preset is a custom board size
+type Preset struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	Bombs  int `json:"bombs"`
+}
+
+// Settings are the choices of the user that are kept between launches
+type Settings struct {
+	Version    int               `json:"version"`
+	Width      int               `json:"width"`
+	Height     int               `json:"height"`
+	Bombs      int               `json:"bombs"`
+	Presets    []Preset          `json:"presets"`
+	Scale      int               `json:"scale"`
+	Skin       string            `json:"skin"`
+	FirstClick engine.FirstClick `json:"firstClick"`
+	NoGuess    bool              `json:"noGuess"`
+	Questions  bool              `json:"questions"`
+	Holding    int               `json:"holding"`
+	Volume     float32           `json:"volume"`
+	Muted      bool              `json:"muted"`
+	Name       string            `json:"name"`
+}
+
+// Default gets the settings of a first launch
+func Default() Settings {
+	return Settings{
+		Version: Version,
+		Width:   9,
+		Height:  9,
+		Bombs:   10,
+		Presets: []Preset{},
+		Scale:   2,
+		Holding: 15,
+		Volume:  1,
+	}
+}
+
+// DefaultFileName gets the settings file in the config directory
+func DefaultFileName() (string, error) {
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "settings.json"), nil
+}
+
+// Load reads the settings file, a missing file gives the default settings, an
+// invalid file gives the default settings and the error
+func Load(fileName string) (Settings, error) {
+	data, err := os.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Default(), err
+	}
+	s, err := Unmarshal(data)
+	if err != nil {
+		return Default(), err
+	}
+	return s, nil
+}
+
+// Unmarshal decodes the settings, migrating them from older versions
+func Unmarshal(data []byte) (Settings, error) {
+	raw := map[string]interface{}{}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return Settings{}, err
+	}
+	version, ok := raw["version"].(float64)
+	if !ok || version < 1 || int(version) > Version {
+		return Settings{}, fmt.Errorf("Unmarshal: unsupported settings version %v", raw["version"])
+	}
+	for v := int(version); v < Version; v++ {
+		migrations[v-1](raw)
+	}
+	raw["version"] = Version
+	data, err = json.Marshal(raw)
+	if err != nil {
+		return Settings{}, err
+	}
+	s := Default()
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return Settings{}, err
+	}
+	s.validate()
+	return s, nil
+}
+
+// validate replaces values that are out of range with their defaults
+func (s *Settings) validate() {
+	d := Default()
+	if !(Preset{Width: s.Width, Height: s.Height, Bombs: s.Bombs}).IsValid() {
+		s.Width, s.Height, s.Bombs = d.Width, d.Height, d.Bombs
+	}
+	if s.Scale < 1 || s.Scale > 6 {
+		s.Scale = d.Scale
+	}
+	if s.FirstClick < 0 || int(s.FirstClick) >= len(engine.FirstClicks) {
+		s.FirstClick = d.FirstClick
+	}
+	if s.Holding < 0 {
+		s.Holding = d.Holding
+	}
+	if s.Volume < 0 || s.Volume > 1 {
+		s.Volume = d.Volume
+	}
+	presets := []Preset{}
+	for _, p := range s.Presets {
+		if p.IsValid() {
+			presets = append(presets, p)
+		}
+	}
+	if len(presets) > MaxPresets {
+		presets = presets[len(presets)-MaxPresets:]
+	}
+	s.Presets = presets
+}
+
+// IsValid returns whether or not the board size can be played
+func (p Preset) IsValid() bool {
+	return p.Width >= 9 && p.Width <= 100 && p.Height >= 9 && p.Height <= 50 && p.Bombs >= 1 && p.Bombs < p.Width*p.Height
+}
+
+// AddPreset keeps a custom board size, the oldest preset is dropped when there are too many
+func (s *Settings) AddPreset(p Preset) {
+	for _, preset := range s.Presets {
+		if preset == p {
+			return
+		}
+	}
+	s.Presets = append(s.Presets, p)
+	if len(s.Presets) > MaxPresets {
+		s.Presets = s.Presets[1:]
+	}
+}
+
+// Save writes the settings file atomically
+func (s Settings) Save(fileName string) error {
+	s.Version = Version
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return xdg.WriteFile(fileName, data)
+}
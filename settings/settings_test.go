@@ -0,0 +1,117 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, Default()) {
+		t.Errorf("expected the default settings, got %+v", s)
+	}
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "settings.json")
+	for _, data := range []string{"{", `{"version":0}`, `{"version":3}`, `{"version":"1"}`, `{"version":2,"width":"wide"}`} {
+		if err := os.WriteFile(fileName, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		s, err := Load(fileName)
+		if err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+		if !reflect.DeepEqual(s, Default()) {
+			t.Errorf("%s: expected the default settings, got %+v", data, s)
+		}
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "settings.json")
+	s := Default()
+	s.Width, s.Height, s.Bombs = 30, 16, 99
+	s.Presets = []Preset{{Width: 20, Height: 20, Bombs: 80}}
+	s.Scale, s.Skin, s.FirstClick = 3, "dark.png", 2
+	s.NoGuess, s.Questions, s.Holding = true, true, 0
+	s.Volume, s.Muted, s.Name = 0.5, true, "Ada"
+	if err := s.Save(fileName); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, s) {
+		t.Errorf("expected %+v, got %+v", s, loaded)
+	}
+}
+
+func TestMigrateVersion1(t *testing.T) {
+	// version 1 had no migrations and could have more bombs than cells
+	s, err := Unmarshal([]byte(`{"version":1,"width":9,"height":9,"bombs":81,"scale":3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != Version || s.Width != 9 || s.Height != 9 || s.Bombs != 80 || s.Scale != 3 {
+		t.Errorf("expected a 9x9 board with 80 bombs at scale 3, got %+v", s)
+	}
+	// missing fields get their default value
+	if s.Holding != 15 || s.Volume != 1 || s.Presets == nil {
+		t.Errorf("expected the default values of missing fields, got %+v", s)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	d := Default()
+	tests := []struct {
+		name     string
+		data     string
+		expected func(s *Settings)
+	}{
+		{"too few bombs", `{"version":2,"width":9,"height":9,"bombs":0}`, func(s *Settings) {}},
+		{"too many bombs", `{"version":2,"width":9,"height":9,"bombs":81}`, func(s *Settings) {}},
+		{"too narrow", `{"version":2,"width":8,"height":9,"bombs":10}`, func(s *Settings) {}},
+		{"too high", `{"version":2,"width":9,"height":51,"bombs":10}`, func(s *Settings) {}},
+		{"scale", `{"version":2,"scale":7}`, func(s *Settings) {}},
+		{"first click", `{"version":2,"firstClick":9}`, func(s *Settings) {}},
+		{"holding", `{"version":2,"holding":-1}`, func(s *Settings) {}},
+		{"volume", `{"version":2,"volume":1.5}`, func(s *Settings) {}},
+		{"invalid preset", `{"version":2,"presets":[{"width":9,"height":9,"bombs":81}]}`, func(s *Settings) {}},
+		{"too many presets", `{"version":2,"presets":[` +
+			`{"width":9,"height":9,"bombs":10},{"width":10,"height":10,"bombs":10},` +
+			`{"width":11,"height":11,"bombs":10},{"width":12,"height":12,"bombs":10}]}`,
+			func(s *Settings) {
+				s.Presets = []Preset{{Width: 10, Height: 10, Bombs: 10}, {Width: 11, Height: 11, Bombs: 10}, {Width: 12, Height: 12, Bombs: 10}}
+			}},
+	}
+	for _, test := range tests {
+		s, err := Unmarshal([]byte(test.data))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		expected := d
+		test.expected(&expected)
+		if !reflect.DeepEqual(s, expected) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, expected, s)
+		}
+	}
+}
+
+func TestAddPreset(t *testing.T) {
+	s := Default()
+	presets := []Preset{{9, 9, 10}, {16, 16, 40}, {16, 16, 40}, {30, 16, 99}, {20, 20, 80}}
+	for _, p := range presets {
+		s.AddPreset(p)
+	}
+	expected := []Preset{{16, 16, 40}, {30, 16, 99}, {20, 20, 80}}
+	if !reflect.DeepEqual(s.Presets, expected) {
+		t.Errorf("expected presets %v, got %v", expected, s.Presets)
+	}
+}
@@ -13,32 +13,72 @@ type SpriteMap map[string]*Sprite
 
 // Sprite is the base struct for any sprite
 type Sprite struct {
-	Image   *rl.Image    `json:"-"`
-	Texture rl.Texture2D `json:"-"`
-	Name    string       `json:"name"`
-	X       int          `json:"x"`
-	Y       int          `json:"y"`
-	Width   int          `json:"width,omitempty"`
-	Height  int          `json:"height,omitempty"`
-	Widths  [3]int       `json:"widths,omitempty"`
-	Heights [3]int       `json:"heights,omitempty"`
-	Count   int          `json:"count"`
-	Grid    int          `json:"grid"`
-	Gap     int          `json:"gap,omitempty"`
+	Image      *rl.Image    `json:"-"`
+	Texture    rl.Texture2D `json:"-"`
+	Name       string       `json:"name" schema:"required"`
+	X          int          `json:"x"`
+	Y          int          `json:"y"`
+	Width      int          `json:"width,omitempty"`
+	Height     int          `json:"height,omitempty"`
+	Widths     [3]int       `json:"widths,omitempty"`
+	Heights    [3]int       `json:"heights,omitempty"`
+	Count      int          `json:"count"`
+	Grid       int          `json:"grid"`
+	Gap        int          `json:"gap,omitempty"`
+	Frames     []Frame      `json:"frames,omitempty"`
+	Animations []Animation  `json:"animations,omitempty"`
 }
 
+// Frame is an explicit frame rectangle of a sprite with a duration in milliseconds
+type Frame struct {
+	X        int `json:"x"`
+	Y        int `json:"y"`
+	Width    int `json:"width"`
+	Height   int `json:"height"`
+	Duration int `json:"duration,omitempty"`
+}
+
+// Animation is a named range of frames of a sprite
+type Animation struct {
+	Name      string `json:"name" schema:"required"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Direction string `json:"direction,omitempty"`
+}
+
+// Animation directions
+const (
+	DirectionForward  = "forward"
+	DirectionReverse  = "reverse"
+	DirectionPingPong = "pingpong"
+)
+
 // NewSpriteMap creates a new sprite map
 func NewSpriteMap(imagedata []byte, jsondata string) (SpriteMap, error) {
-	image, err := png.Decode(bytes.NewReader(imagedata))
+	sprites := []*Sprite{}
+	err := json.Unmarshal([]byte(jsondata), &sprites)
 	if err != nil {
 		return nil, err
 	}
-	sprites := []*Sprite{}
-	spriteMap := SpriteMap{}
-	err = json.Unmarshal([]byte(jsondata), &sprites)
+	return FromSprites(imagedata, sprites)
+}
+
+// FromSprites creates a new sprite map from sprites that share an image,
+// without image data the sprites have no image or texture (e.g. to run a
+// movie without a window)
+func FromSprites(imagedata []byte, sprites []*Sprite) (SpriteMap, error) {
+	if imagedata == nil {
+		spriteMap := SpriteMap{}
+		for _, sprite := range sprites {
+			spriteMap[sprite.Name] = sprite
+		}
+		return spriteMap, nil
+	}
+	image, err := png.Decode(bytes.NewReader(imagedata))
 	if err != nil {
 		return nil, err
 	}
+	spriteMap := SpriteMap{}
 	spriteImage := rl.NewImageFromImage(image)
 	spriteTexture := rl.LoadTextureFromImage(spriteImage)
 	for _, sprite := range sprites {
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strconv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/mevdschee/raylib-go-mines/engine"
+)
+
+// finish calculates the stats of the game that just ended
+func (g *game) finish() {
+	if g.board == nil {
+		return
+	}
+	// the clock advances in ticks, so the time is a whole number of ticks and
+	// a replay of the game gets exactly the same time
+	milliseconds := (g.clock.Now().UnixNano() - g.time) / 1000000
+	stats := engine.NewStats(g.board, g.getView(), g.clicks, milliseconds)
+	stats.Hints = g.hints
+	g.stats = &stats
+	g.showStats = true
+	g.addScore()
+	g.finishDaily()
+	g.saveGame()
+}
+
+// getStatsLines gets the lines of the end-of-game panel
+func (g *game) getStatsLines() []string {
+	s := g.stats
+	format := func(f float64, decimals int) string {
+		return strconv.FormatFloat(f, 'f', decimals, 64)
+	}
+	lines := []string{
+		"Time: " + format(s.Seconds(), 3) + " s (in ticks of 1/" + strconv.Itoa(ticksPerSecond) + " s)",
+		"3BV: " + strconv.Itoa(s.Solved) + "/" + strconv.Itoa(s.BBBV) + "  3BV/s: " + format(s.BBBVPerSecond(), 2),
+		"Clicks: " + strconv.Itoa(s.Clicks.Total()) + " (" + strconv.Itoa(s.Clicks.Left) + " left, " +
+			strconv.Itoa(s.Clicks.Right) + " right, " + strconv.Itoa(s.Clicks.Chord) + " chord)",
+		"IOE: " + format(s.IOE(), 2) + "  Efficiency: " + format(s.Efficiency(), 0) + "%",
+		"ZiNi: " + strconv.Itoa(s.ZiNi) + "  Human ZiNi: " + strconv.Itoa(s.HumanZiNi),
+		"RQP: " + format(s.RQP(), 2),
+		"Hints: " + strconv.Itoa(s.Hints),
+	}
+	if g.c.daily != "" {
+		lines = append(lines, g.getDailyLine())
+	}
+	switch {
+	case g.rank >= 0:
+		lines = append(lines, "High score #"+strconv.Itoa(g.rank+1)+"! Press T to see all")
+	case g.state == stateWon && !g.isRanked():
+		lines = append(lines, "Not ranked: practice, help, a chosen board or a replay")
+	}
+	return append(lines, "Press S to hide")
+}
+
+func (g *game) drawStats(scale int) {
+	if g.stats == nil || !g.showStats || (g.state != stateWon && g.state != stateLost) {
+		return
+	}
+	s := int32(scale)
+	width, _ := g.getSize()
+	fontSize := 5 * s
+	lines := []string{}
+	for _, line := range g.getStatsLines() {
+		lines = append(lines, wrapText(line, int32(width*scale)-4*s, fontSize)...)
+	}
+	height := int32(len(lines))*(fontSize+s) + 2*s
+	top := 55 * s
+	rl.DrawRectangle(0, top, int32(width*scale), height, rl.Fade(rl.Black, 0.7))
+	for i, line := range lines {
+		rl.DrawText(line, 2*s, top+s+int32(i)*(fontSize+s), fontSize, rl.White)
+	}
+}
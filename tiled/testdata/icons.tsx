@@ -0,0 +1,4 @@
+<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" tiledversion="1.10.2" name="icons" tilewidth="16" tileheight="16" spacing="1" margin="0" tilecount="17" columns="9">
+ <image source="winxpskin.png" width="256" height="128"/>
+</tileset>
@@ -0,0 +1,289 @@
+package tiled
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mevdschee/raylib-go-mines/clips"
+	"github.com/mevdschee/raylib-go-mines/layers"
+	"github.com/mevdschee/raylib-go-mines/scenes"
+	"github.com/mevdschee/raylib-go-mines/sprites"
+)
+
+// the high bits of a gid are used by Tiled for flipping
+const gidMask = 0x0fffffff
+
+type property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type tileset struct {
+	FirstGID   int    `xml:"firstgid,attr"`
+	Source     string `xml:"source,attr"`
+	Name       string `xml:"name,attr"`
+	TileWidth  int    `xml:"tilewidth,attr"`
+	TileHeight int    `xml:"tileheight,attr"`
+	Spacing    int    `xml:"spacing,attr"`
+	Margin     int    `xml:"margin,attr"`
+	TileCount  int    `xml:"tilecount,attr"`
+	Columns    int    `xml:"columns,attr"`
+}
+
+type data struct {
+	Encoding string `xml:"encoding,attr"`
+	Text     string `xml:",chardata"`
+	Tiles    []struct {
+		GID int `xml:"gid,attr"`
+	} `xml:"tile"`
+}
+
+type tileLayer struct {
+	Name   string `xml:"name,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+	Data   data   `xml:"data"`
+}
+
+type object struct {
+	Name       string     `xml:"name,attr"`
+	GID        uint32     `xml:"gid,attr"`
+	X          float64    `xml:"x,attr"`
+	Y          float64    `xml:"y,attr"`
+	Width      float64    `xml:"width,attr"`
+	Height     float64    `xml:"height,attr"`
+	Properties []property `xml:"properties>property"`
+}
+
+type objectGroup struct {
+	Name    string   `xml:"name,attr"`
+	Objects []object `xml:"object"`
+}
+
+type layer struct {
+	tiles   *tileLayer
+	objects *objectGroup
+}
+
+type tiledMap struct {
+	Width      int       `xml:"width,attr"`
+	Height     int       `xml:"height,attr"`
+	TileWidth  int       `xml:"tilewidth,attr"`
+	TileHeight int       `xml:"tileheight,attr"`
+	Tilesets   []tileset `xml:"tileset"`
+	Layers     []layer   `xml:",any"`
+}
+
+// UnmarshalXML keeps tile layers and object groups in document order
+func (l *layer) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name.Local {
+	case "layer":
+		l.tiles = &tileLayer{}
+		return d.DecodeElement(l.tiles, &start)
+	case "objectgroup":
+		l.objects = &objectGroup{}
+		return d.DecodeElement(l.objects, &start)
+	}
+	return d.Skip()
+}
+
+// ReadTileset reads a TSX tileset as a sprite
+func ReadTileset(tsx []byte) (*sprites.Sprite, error) {
+	t := tileset{}
+	err := xml.Unmarshal(tsx, &t)
+	if err != nil {
+		return nil, err
+	}
+	return toSprite(t), nil
+}
+
+func toSprite(t tileset) *sprites.Sprite {
+	return &sprites.Sprite{
+		Name:   t.Name,
+		X:      t.Margin,
+		Y:      t.Margin,
+		Width:  t.TileWidth,
+		Height: t.TileHeight,
+		Count:  t.TileCount,
+		Grid:   t.Columns,
+		Gap:    t.Spacing,
+	}
+}
+
+// ReadMap reads a TMX map as a scene in JSON and returns the sprites of its
+// tilesets, external tilesets are read using the open function
+func ReadMap(name string, tmx []byte, open func(source string) ([]byte, error)) (scenes.SceneJSON, []*sprites.Sprite, error) {
+	m := tiledMap{}
+	err := xml.Unmarshal(tmx, &m)
+	if err != nil {
+		return scenes.SceneJSON{}, nil, err
+	}
+	if m.Width <= 0 || m.Height <= 0 || m.TileWidth <= 0 || m.TileHeight <= 0 {
+		return scenes.SceneJSON{}, nil, fmt.Errorf("Invalid map size %dx%d with tiles of %dx%d", m.Width, m.Height, m.TileWidth, m.TileHeight)
+	}
+	for i, t := range m.Tilesets {
+		if t.Source == "" {
+			continue
+		}
+		tsx, err := open(t.Source)
+		if err != nil {
+			return scenes.SceneJSON{}, nil, fmt.Errorf("Could not open tileset '%s': %v", t.Source, err)
+		}
+		external := tileset{}
+		err = xml.Unmarshal(tsx, &external)
+		if err != nil {
+			return scenes.SceneJSON{}, nil, fmt.Errorf("Tileset '%s': %v", t.Source, err)
+		}
+		external.FirstGID = t.FirstGID
+		m.Tilesets[i] = external
+	}
+	spriteList := []*sprites.Sprite{}
+	for _, t := range m.Tilesets {
+		spriteList = append(spriteList, toSprite(t))
+	}
+	sceneJSON := scenes.SceneJSON{Name: name, Layers: []layers.LayerJSON{}}
+	for _, l := range m.Layers {
+		var layerJSON layers.LayerJSON
+		switch {
+		case l.tiles != nil:
+			layerJSON, err = m.readTileLayer(*l.tiles)
+		case l.objects != nil:
+			layerJSON, err = m.readObjectGroup(*l.objects)
+		default:
+			continue
+		}
+		if err != nil {
+			return scenes.SceneJSON{}, nil, err
+		}
+		sceneJSON.Layers = append(sceneJSON.Layers, layerJSON)
+	}
+	return sceneJSON, spriteList, nil
+}
+
+// LoadScene creates a new scene from a TMX map, the sprite map must contain the tilesets
+func LoadScene(spriteMap sprites.SpriteMap, name string, tmx []byte, open func(source string) ([]byte, error), parameters map[string]interface{}) (*scenes.Scene, error) {
+	sceneJSON, _, err := ReadMap(name, tmx, open)
+	if err != nil {
+		return nil, err
+	}
+	return scenes.FromJSON(spriteMap, sceneJSON, parameters)
+}
+
+func (m *tiledMap) findTileset(gid uint32) (string, int, error) {
+	id := int(gid & gidMask)
+	found := -1
+	for i, t := range m.Tilesets {
+		if t.FirstGID <= id && (found < 0 || t.FirstGID > m.Tilesets[found].FirstGID) {
+			found = i
+		}
+	}
+	if found < 0 {
+		return "", 0, fmt.Errorf("Could not find tileset for gid %d", id)
+	}
+	return m.Tilesets[found].Name, id - m.Tilesets[found].FirstGID, nil
+}
+
+func (m *tiledMap) readTileLayer(l tileLayer) (layers.LayerJSON, error) {
+	layerJSON := layers.LayerJSON{Name: l.Name, Clips: []clips.ClipJSON{}}
+	if l.Width <= 0 || l.Height <= 0 {
+		return layerJSON, fmt.Errorf("Layer '%s': invalid size %dx%d", l.Name, l.Width, l.Height)
+	}
+	gids, err := readData(l.Data)
+	if err != nil {
+		return layerJSON, fmt.Errorf("Layer '%s': %v", l.Name, err)
+	}
+	if len(gids) != l.Width*l.Height {
+		return layerJSON, fmt.Errorf("Layer '%s': expected %d tiles, got %d", l.Name, l.Width*l.Height, len(gids))
+	}
+	for i, gid := range gids {
+		if gid&gidMask == 0 {
+			continue
+		}
+		sprite, frame, err := m.findTileset(gid)
+		if err != nil {
+			return layerJSON, fmt.Errorf("Layer '%s': %v", l.Name, err)
+		}
+		layerJSON.Clips = append(layerJSON.Clips, clips.ClipJSON{
+			Name:   l.Name,
+			Sprite: sprite,
+			X:      strconv.Itoa((i % l.Width) * m.TileWidth),
+			Y:      strconv.Itoa((i / l.Width) * m.TileHeight),
+			Frame:  strconv.Itoa(frame),
+		})
+	}
+	return layerJSON, nil
+}
+
+func readData(d data) ([]uint32, error) {
+	gids := []uint32{}
+	switch d.Encoding {
+	case "csv":
+		for _, field := range strings.Split(d.Text, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			gid, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			gids = append(gids, uint32(gid))
+		}
+	case "":
+		for _, tile := range d.Tiles {
+			gids = append(gids, uint32(tile.GID))
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported encoding '%s', use CSV", d.Encoding)
+	}
+	return gids, nil
+}
+
+func (m *tiledMap) readObjectGroup(g objectGroup) (layers.LayerJSON, error) {
+	layerJSON := layers.LayerJSON{Name: g.Name, Clips: []clips.ClipJSON{}}
+	for _, o := range g.Objects {
+		clipJSON := clips.ClipJSON{
+			Name: o.Name,
+			X:    strconv.Itoa(int(o.X)),
+			Y:    strconv.Itoa(int(o.Y)),
+		}
+		if o.GID != 0 {
+			sprite, frame, err := m.findTileset(o.GID)
+			if err != nil {
+				return layerJSON, fmt.Errorf("Object '%s': %v", o.Name, err)
+			}
+			clipJSON.Sprite = sprite
+			clipJSON.Frame = strconv.Itoa(frame)
+			// tile objects are positioned by their bottom left corner
+			clipJSON.Y = strconv.Itoa(int(o.Y - o.Height))
+		} else if o.Width > 0 && o.Height > 0 {
+			clipJSON.Width = strconv.Itoa(int(o.Width))
+			clipJSON.Height = strconv.Itoa(int(o.Height))
+		}
+		for _, p := range o.Properties {
+			switch p.Name {
+			case "sprite":
+				clipJSON.Sprite = p.Value
+			case "repeat":
+				clipJSON.Repeat = p.Value
+			case "x":
+				clipJSON.X = p.Value
+			case "y":
+				clipJSON.Y = p.Value
+			case "width":
+				clipJSON.Width = p.Value
+			case "height":
+				clipJSON.Height = p.Value
+			case "frame":
+				clipJSON.Frame = p.Value
+			}
+		}
+		if clipJSON.Sprite == "" {
+			return layerJSON, fmt.Errorf("Object '%s' has no tile or 'sprite' property", o.Name)
+		}
+		layerJSON.Clips = append(layerJSON.Clips, clipJSON)
+	}
+	return layerJSON, nil
+}
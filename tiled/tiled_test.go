@@ -0,0 +1,98 @@
+package tiled
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mevdschee/raylib-go-mines/clips"
+	"github.com/mevdschee/raylib-go-mines/sprites"
+)
+
+func open(source string) ([]byte, error) {
+	return os.ReadFile(filepath.Join("testdata", source))
+}
+
+func TestReadTileset(t *testing.T) {
+	tsx, err := open("icons.tsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sprite, err := ReadTileset(tsx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &sprites.Sprite{Name: "icons", Width: 16, Height: 16, Count: 17, Grid: 9, Gap: 1}
+	if !reflect.DeepEqual(sprite, expected) {
+		t.Errorf("expected %+v, got %+v", expected, sprite)
+	}
+}
+
+func TestReadMap(t *testing.T) {
+	tmx, err := open("board.tmx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scene, spriteList, err := ReadMap("game", tmx, open)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spriteList) != 2 || spriteList[0].Name != "icons" || spriteList[1].Name != "digits" {
+		t.Fatalf("expected sprites icons and digits, got %v", spriteList)
+	}
+	if scene.Name != "game" || len(scene.Layers) != 2 {
+		t.Fatalf("expected scene 'game' with 2 layers, got %+v", scene)
+	}
+	icons := scene.Layers[0]
+	expected := []clips.ClipJSON{
+		{Name: "icons", Sprite: "icons", X: "0", Y: "0", Frame: "9"},
+		{Name: "icons", Sprite: "icons", X: "32", Y: "0", Frame: "1"},
+		{Name: "icons", Sprite: "icons", X: "0", Y: "16", Frame: "0"},
+		// the flipping bits of the gid are ignored
+		{Name: "icons", Sprite: "icons", X: "16", Y: "16", Frame: "9"},
+		{Name: "icons", Sprite: "icons", X: "32", Y: "16", Frame: "11"},
+	}
+	if icons.Name != "icons" || !reflect.DeepEqual(icons.Clips, expected) {
+		t.Errorf("expected clips %+v, got %+v", expected, icons.Clips)
+	}
+	fg := scene.Layers[1]
+	expected = []clips.ClipJSON{
+		{Name: "bombs", Sprite: "digits", Repeat: "3", X: "18+i*13", Y: "17", Frame: "0"},
+		{Name: "field", Sprite: "field", X: "0", Y: "44", Width: "w*16+24", Height: "54"},
+	}
+	if fg.Name != "fg" || !reflect.DeepEqual(fg.Clips, expected) {
+		t.Errorf("expected clips %+v, got %+v", expected, fg.Clips)
+	}
+}
+
+func TestReadMapErrors(t *testing.T) {
+	tmx, err := open("board.tmx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		old, new string
+		err      string
+	}{
+		{`name="icons" width="3"`, `name="icons" width="0"`, "Layer 'icons': invalid size 0x2"},
+		{`name="icons" width="3" height="2"`, `name="icons" width="3" height="0"`, "Layer 'icons': invalid size 3x0"},
+		{`1,2147483658,12`, `1,2147483658`, "Layer 'icons': expected 6 tiles, got 5"},
+		{`encoding="csv"`, `encoding="base64"`, "Unsupported encoding 'base64'"},
+		{`width="3" height="2" tilewidth="16"`, `width="3" height="2" tilewidth="0"`, "Invalid map size"},
+		{`source="icons.tsx"`, `source="missing.tsx"`, "Could not open tileset 'missing.tsx'"},
+		{`firstgid="1"`, `firstgid="2"`, "Could not find tileset for gid 1"},
+		{`<property name="sprite" value="field"/>`, ``, "Object 'field' has no tile or 'sprite' property"},
+	}
+	for _, test := range tests {
+		if !strings.Contains(string(tmx), test.old) {
+			t.Fatalf("fixture does not contain '%s'", test.old)
+		}
+		broken := strings.Replace(string(tmx), test.old, test.new, 1)
+		_, _, err := ReadMap("game", []byte(broken), open)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("expected error '%s', got %v", test.err, err)
+		}
+	}
+}
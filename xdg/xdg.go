@@ -0,0 +1,61 @@
+package xdg
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// App is the name of the directory of the game in the data and config directories
+const App = "raylib-go-mines"
+
+// DataDir gets the directory for the data files of the game (e.g. ~/.local/share/raylib-go-mines)
+func DataDir() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+			return ConfigDir()
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, App), nil
+}
+
+// ConfigDir gets the directory for the config files of the game (e.g. ~/.config/raylib-go-mines)
+func ConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, App), nil
+}
+
+// WriteFile writes a file atomically by writing a temporary file and renaming it, creating the directory if needed
+func WriteFile(fileName string, data []byte) error {
+	dir := filepath.Dir(fileName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), fileName)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}